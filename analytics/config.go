@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	caerusanalytics "github.com/desmos-labs/caerus/analytics"
+	caerusutils "github.com/desmos-labs/caerus/utils"
+)
+
+const (
+	// SinkNone disables the analytics, discarding all the events
+	SinkNone = "none"
+
+	// SinkPostHog sends the events to PostHog. The PostHog client is configured using the ANALYTICS_ENABLED,
+	// ANALYTICS_POSTHOG_API_KEY and ANALYTICS_POSTHOG_HOST env variables
+	SinkPostHog = "posthog"
+
+	// SinkFile writes the events to a file, one JSON object per line, allowing to use the analytics offline
+	SinkFile = "file"
+)
+
+// Config contains the configuration of the analytics
+type Config struct {
+	// Sink represents where the events are sent (either SinkNone, SinkPostHog or SinkFile)
+	Sink string `yaml:"sink"`
+
+	// FilePath represents the path of the file where the events are written when using SinkFile
+	FilePath string `yaml:"file_path"`
+
+	// QueueSize represents the maximum number of events waiting to be sent.
+	// When the queue is full, the new events are discarded so that the requests are never slowed down
+	QueueSize int `yaml:"queue_size"`
+}
+
+// DefaultConfig returns the default Config instance, which uses SinkPostHog when the PostHog client is enabled,
+// and SinkNone otherwise
+func DefaultConfig() *Config {
+	sink := SinkNone
+	if isPostHogEnabled() {
+		sink = SinkPostHog
+	}
+
+	return &Config{
+		Sink:      sink,
+		QueueSize: 1000,
+	}
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.Sink {
+	case SinkNone:
+		break
+	case SinkPostHog:
+		if !isPostHogEnabled() {
+			errs = append(errs, fmt.Errorf("the %s sink requires %s to be true and %s to be set",
+				SinkPostHog, caerusanalytics.EnvAnalyticsEnabled, caerusanalytics.EnvAnalyticsPostHogApiKey))
+		}
+	case SinkFile:
+		if c.FilePath == "" {
+			errs = append(errs, fmt.Errorf("missing file_path (%s)", EnvAnalyticsFilePath))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid sink %q: must be either %s, %s or %s", c.Sink, SinkNone, SinkPostHog, SinkFile))
+	}
+
+	if c.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("invalid queue_size: must be a positive integer"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// isPostHogEnabled tells whether the PostHog client of the Caerus analytics package has been enabled
+func isPostHogEnabled() bool {
+	enabled, _ := strconv.ParseBool(caerusutils.GetEnvOr(caerusanalytics.EnvAnalyticsEnabled, "false"))
+	apiKey := caerusutils.GetEnvOr(caerusanalytics.EnvAnalyticsPostHogApiKey, "")
+	return enabled && strings.TrimSpace(apiKey) != ""
+}
@@ -0,0 +1,7 @@
+package analytics
+
+const (
+	EnvAnalyticsSink      = "ANALYTICS_SINK"
+	EnvAnalyticsFilePath  = "ANALYTICS_FILE_PATH"
+	EnvAnalyticsQueueSize = "ANALYTICS_QUEUE_SIZE"
+)
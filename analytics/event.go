@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	// EventCreateLink represents the event tracked each time the creation of a deep link is requested
+	EventCreateLink = "create_deep_link"
+
+	// EventGetLinkConfig represents the event tracked each time the configuration of a deep link is requested
+	EventGetLinkConfig = "get_deep_link_config"
+)
+
+// Event represents a usage event.
+// The events must never contain any address, so that the analytics do not store any personal data
+type Event struct {
+	// Name represents the name of the event (e.g. EventCreateLink)
+	Name string `json:"event"`
+
+	// LinkType represents the type of the link involved, if known (e.g. "address", "send" or "custom")
+	LinkType string `json:"link_type,omitempty"`
+
+	// ChainType represents the chain type of the link involved, if any (either "mainnet" or "testnet")
+	ChainType string `json:"chain_type,omitempty"`
+
+	// ClientKey represents the identifier of the API key used to perform the request, if any
+	ClientKey string `json:"client_key,omitempty"`
+
+	// Success tells whether the operation succeeded
+	Success bool `json:"success"`
+
+	// ErrorCode represents the code of the error returned to the client when the operation failed
+	ErrorCode string `json:"error_code,omitempty"`
+
+	// Timestamp represents when the event happened
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewEvent returns a new Event instance having the given name, happened now.
+// The operation is considered successful if the given error is nil, otherwise the code of the error is included
+func NewEvent(name string, linkType string, chainType string, clientKey string, err error) *Event {
+	event := &Event{
+		Name:      name,
+		LinkType:  linkType,
+		ChainType: chainType,
+		ClientKey: clientKey,
+		Success:   err == nil,
+		Timestamp: time.Now(),
+	}
+
+	if err != nil {
+		event.ErrorCode = utils.UnwrapErr(err).Code
+	}
+
+	return event
+}
@@ -0,0 +1,128 @@
+package analytics
+
+import (
+	"encoding/json"
+	"os"
+
+	caerusanalytics "github.com/desmos-labs/caerus/analytics"
+	"github.com/posthog/posthog-go"
+)
+
+// Sink represents the destination of the tracked events
+type Sink interface {
+	// Send sends the given event
+	Send(event *Event) error
+
+	// Close sends all the pending events and releases the resources used by the sink
+	Close() error
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+var (
+	_ Sink = noopSink{}
+)
+
+// noopSink is a Sink that discards all the events
+type noopSink struct{}
+
+// NewNoopSink returns a new Sink that discards all the events
+func NewNoopSink() Sink {
+	return noopSink{}
+}
+
+// Send implements Sink
+func (noopSink) Send(*Event) error {
+	return nil
+}
+
+// Close implements Sink
+func (noopSink) Close() error {
+	return nil
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+var (
+	_ Sink = &fileSink{}
+)
+
+// fileSink is a Sink that writes the events to a file, one JSON object per line
+type fileSink struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewFileSink returns a new Sink that appends the events to the file at the given path, creating it if needed
+func NewFileSink(path string) (Sink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// Send implements Sink
+func (s *fileSink) Send(event *Event) error {
+	return s.encoder.Encode(event)
+}
+
+// Close implements Sink
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+var (
+	_ Sink = postHogSink{}
+)
+
+// anonymousDistinctID represents the PostHog distinct ID used for the events that have no client key
+const anonymousDistinctID = "anonymous"
+
+// postHogSink is a Sink that sends the events to PostHog using the Caerus analytics package
+type postHogSink struct{}
+
+// NewPostHogSink returns a new Sink that sends the events to PostHog using the Caerus analytics package
+func NewPostHogSink() Sink {
+	return postHogSink{}
+}
+
+// Send implements Sink
+func (postHogSink) Send(event *Event) error {
+	distinctID := event.ClientKey
+	if distinctID == "" {
+		distinctID = anonymousDistinctID
+	}
+
+	properties := posthog.NewProperties().
+		Set("success", event.Success)
+	if event.LinkType != "" {
+		properties.Set("link_type", event.LinkType)
+	}
+	if event.ChainType != "" {
+		properties.Set("chain_type", event.ChainType)
+	}
+	if event.ErrorCode != "" {
+		properties.Set("error_code", event.ErrorCode)
+	}
+
+	caerusanalytics.Enqueue(posthog.Capture{
+		DistinctId: distinctID,
+		Event:      event.Name,
+		Timestamp:  event.Timestamp,
+		Properties: properties,
+	})
+	return nil
+}
+
+// Close implements Sink
+func (postHogSink) Close() error {
+	caerusanalytics.Stop()
+	return nil
+}
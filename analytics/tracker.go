@@ -0,0 +1,123 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/desmos-labs/dpm-apis/redaction"
+)
+
+// Tracker tracks the usage events, sending them asynchronously to a Sink so that the requests are never slowed down
+type Tracker struct {
+	sink     Sink
+	redactor *redaction.Redactor
+
+	events chan *Event
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+// NewTracker returns a new Tracker instance sending the events to the given sink, queueing at most queueSize
+// events. Any address contained inside the events is replaced with its keyed hash using the given redactor.
+// The returned Tracker must be stopped before exiting so that all the pending events are sent
+func NewTracker(sink Sink, redactor *redaction.Redactor, queueSize int) *Tracker {
+	tracker := &Tracker{
+		sink:     sink,
+		redactor: redactor,
+		events:   make(chan *Event, queueSize),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go tracker.run()
+	return tracker
+}
+
+// Setup returns a new Tracker instance based on the given configuration
+func Setup(cfg *Config, redactor *redaction.Redactor) (*Tracker, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var sink Sink
+	switch cfg.Sink {
+	case SinkNone:
+		sink = NewNoopSink()
+
+	case SinkPostHog:
+		sink = NewPostHogSink()
+
+	case SinkFile:
+		fileSink, err := NewFileSink(cfg.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		sink = fileSink
+
+	default:
+		return nil, fmt.Errorf("unsupported sink: %s", cfg.Sink)
+	}
+
+	if cfg.Sink != SinkNone {
+		log.Info().Str("sink", cfg.Sink).Msg("Analytics enabled")
+	}
+
+	return NewTracker(sink, redactor, cfg.QueueSize), nil
+}
+
+// Track queues the given event so that it is sent to the sink.
+// If the queue is full the event is discarded, so that this method never blocks
+func (t *Tracker) Track(event *Event) {
+	event.ClientKey = t.redactor.Redact(redaction.ModeHash, event.ClientKey)
+
+	select {
+	case t.events <- event:
+	default:
+		log.Debug().Str("event", event.Name).Msg("analytics queue full, discarding event")
+	}
+}
+
+// run sends the queued events to the sink until the tracker is stopped, after which the pending events are sent
+func (t *Tracker) run() {
+	defer close(t.done)
+
+	for {
+		select {
+		case event := <-t.events:
+			t.send(event)
+
+		case <-t.quit:
+			for {
+				select {
+				case event := <-t.events:
+					t.send(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send sends the given event to the sink, logging any error that occurs
+func (t *Tracker) send(event *Event) {
+	err := t.sink.Send(event)
+	if err != nil {
+		log.Error().Err(err).Str("event", event.Name).Msg("error while sending analytics event")
+	}
+}
+
+// Stop sends all the pending events and closes the sink, waiting at most until the given context is done
+func (t *Tracker) Stop(ctx context.Context) error {
+	close(t.quit)
+
+	select {
+	case <-t.done:
+		return t.sink.Close()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,191 @@
+package analytics_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/redaction"
+)
+
+const (
+	testAddress = "desmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+)
+
+func TestTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(TrackerTestSuite))
+}
+
+// TrackerTestSuite tests the asynchronous delivery of the events to the sinks
+type TrackerTestSuite struct {
+	suite.Suite
+
+	redactor *redaction.Redactor
+}
+
+// recordingSink is an analytics.Sink that records all the operations performed on it.
+// When blocking, each call to Send waits until the sink is released
+type recordingSink struct {
+	mu         sync.Mutex
+	operations []string
+	events     []*analytics.Event
+
+	sending chan struct{}
+	release chan struct{}
+}
+
+func newRecordingSink() *recordingSink {
+	release := make(chan struct{})
+	close(release)
+	return &recordingSink{sending: make(chan struct{}, 100), release: release}
+}
+
+func newBlockingSink() *recordingSink {
+	return &recordingSink{sending: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (s *recordingSink) Send(event *analytics.Event) error {
+	s.sending <- struct{}{}
+	<-s.release
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations = append(s.operations, "send:"+event.Name)
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations = append(s.operations, "close")
+	return nil
+}
+
+// Operations returns all the operations performed on the sink so far
+func (s *recordingSink) Operations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.operations...)
+}
+
+// Events returns all the events sent to the sink so far
+func (s *recordingSink) Events() []*analytics.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*analytics.Event{}, s.events...)
+}
+
+func (suite *TrackerTestSuite) SetupTest() {
+	suite.redactor = redaction.NewRedactor(&redaction.Config{Key: "redaction-test-key", Mode: redaction.ModeHash})
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+func (suite *TrackerTestSuite) TestStop_SendsPendingEventsBeforeClosing() {
+	sink := newRecordingSink()
+	tracker := analytics.NewTracker(sink, suite.redactor, 10)
+
+	tracker.Track(&analytics.Event{Name: "first"})
+	tracker.Track(&analytics.Event{Name: "second"})
+	tracker.Track(&analytics.Event{Name: "third"})
+
+	suite.Require().NoError(tracker.Stop(context.Background()))
+	suite.Require().Equal([]string{"send:first", "send:second", "send:third", "close"}, sink.Operations())
+}
+
+func (suite *TrackerTestSuite) TestTrack_QueueFull() {
+	sink := newBlockingSink()
+	tracker := analytics.NewTracker(sink, suite.redactor, 1)
+
+	// The first event is being sent, while the second one fills the queue
+	tracker.Track(&analytics.Event{Name: "first"})
+	<-sink.sending
+	tracker.Track(&analytics.Event{Name: "second"})
+
+	// Any other event should be discarded without blocking
+	tracked := make(chan struct{})
+	go func() {
+		defer close(tracked)
+		tracker.Track(&analytics.Event{Name: "third"})
+	}()
+
+	select {
+	case <-tracked:
+	case <-time.After(time.Second):
+		suite.FailNow("Track blocked while the queue was full")
+	}
+
+	close(sink.release)
+	suite.Require().NoError(tracker.Stop(context.Background()))
+	suite.Require().Equal([]string{"send:first", "send:second", "close"}, sink.Operations())
+}
+
+func (suite *TrackerTestSuite) TestStop_Timeout() {
+	sink := newBlockingSink()
+	defer close(sink.release)
+
+	tracker := analytics.NewTracker(sink, suite.redactor, 10)
+	tracker.Track(&analytics.Event{Name: "first"})
+	<-sink.sending
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := tracker.Stop(ctx)
+	suite.Require().True(errors.Is(err, context.DeadlineExceeded))
+	suite.Require().NotContains(sink.Operations(), "close")
+}
+
+func (suite *TrackerTestSuite) TestTrack_HashesClientKey() {
+	sink := newRecordingSink()
+	tracker := analytics.NewTracker(sink, suite.redactor, 10)
+
+	tracker.Track(&analytics.Event{Name: "wallet", ClientKey: testAddress})
+	suite.Require().NoError(tracker.Stop(context.Background()))
+
+	events := sink.Events()
+	suite.Require().Len(events, 1)
+	suite.Require().Equal(suite.redactor.HashAddress(testAddress), events[0].ClientKey)
+}
+
+func (suite *TrackerTestSuite) TestFileSink() {
+	path := filepath.Join(suite.T().TempDir(), "events.jsonl")
+	sink, err := analytics.NewFileSink(path)
+	suite.Require().NoError(err)
+
+	tracker := analytics.NewTracker(sink, suite.redactor, 10)
+	tracker.Track(analytics.NewEvent(analytics.EventCreateLink, "address", "mainnet", testAddress, nil))
+	tracker.Track(analytics.NewEvent(analytics.EventGetLinkConfig, "", "", "", nil))
+	suite.Require().NoError(tracker.Stop(context.Background()))
+
+	file, err := os.Open(path)
+	suite.Require().NoError(err)
+	defer file.Close()
+
+	var events []analytics.Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		suite.Require().NotContains(scanner.Text(), testAddress)
+
+		var event analytics.Event
+		suite.Require().NoError(json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	suite.Require().NoError(scanner.Err())
+
+	suite.Require().Len(events, 2)
+	suite.Require().Equal(analytics.EventCreateLink, events[0].Name)
+	suite.Require().Equal(suite.redactor.HashAddress(testAddress), events[0].ClientKey)
+	suite.Require().True(events[0].Success)
+	suite.Require().Equal(analytics.EventGetLinkConfig, events[1].Name)
+}
@@ -0,0 +1,224 @@
+package authentication
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/desmos-labs/caerus/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	dpmutils "github.com/desmos-labs/dpm-apis/utils"
+)
+
+// Authenticator allows to protect the routes by requiring the requests to be authenticated using either an API key
+// or a wallet session token
+type Authenticator struct {
+	// keys contains the API keys indexed by their hash
+	keys map[string]*APIKey
+
+	// sessions allows to verify the wallet session tokens
+	sessions *SessionsManager
+
+	// disabled tells whether the authentication is disabled and all requests should be allowed
+	disabled bool
+}
+
+// NewAuthenticator returns a new Authenticator instance that accepts the given API keys and
+// the session tokens issued by the given sessions manager
+func NewAuthenticator(keys []*APIKey, sessions *SessionsManager) (*Authenticator, error) {
+	keysByHash, err := indexKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{
+		keys:     keysByHash,
+		sessions: sessions,
+	}, nil
+}
+
+// indexKeys validates the given API keys, returning them indexed by their hash
+func indexKeys(keys []*APIKey) (map[string]*APIKey, error) {
+	keysByHash := make(map[string]*APIKey, len(keys))
+	ids := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		err := key.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		if ids[key.ID] {
+			return nil, fmt.Errorf("duplicated API key id: %s", key.ID)
+		}
+		ids[key.ID] = true
+
+		if _, found := keysByHash[key.Hash]; found {
+			return nil, fmt.Errorf("duplicated hash of API key %s", key.ID)
+		}
+		keysByHash[key.Hash] = key
+	}
+
+	return keysByHash, nil
+}
+
+// NewDisabledAuthenticator returns a new Authenticator instance that allows all the requests
+func NewDisabledAuthenticator(sessions *SessionsManager) *Authenticator {
+	return &Authenticator{
+		sessions: sessions,
+		disabled: true,
+	}
+}
+
+// Config contains the configuration of an Authenticator
+type Config struct {
+	// Disabled tells whether the authentication is disabled and all requests should be allowed
+	Disabled bool
+
+	// Keys contains the accepted API keys
+	Keys []*APIKey
+
+	// Sessions contains the configuration used to handle the wallet sessions
+	Sessions *SessionsConfig
+}
+
+// ReadConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// The keys are read from the JSON file at the path specified by EnvAPIKeysFile, or from the JSON value of EnvAPIKeys.
+// It returns an error if any value is not valid, or if no key is configured unless the authentication is explicitly
+// disabled using EnvAuthDisabled
+func ReadConfigFromEnvVariables() (*Config, error) {
+	disabled, err := strconv.ParseBool(utils.GetEnvOr(EnvAuthDisabled, "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", EnvAuthDisabled, err)
+	}
+
+	sessions, err := ReadSessionsConfigFromEnvVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{Disabled: disabled, Sessions: sessions}
+	if disabled {
+		return cfg, nil
+	}
+
+	var keysBz []byte
+	if keysFile := utils.GetEnvOr(EnvAPIKeysFile, ""); keysFile != "" {
+		keysBz, err = os.ReadFile(keysFile)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading %s: %s", EnvAPIKeysFile, err)
+		}
+	} else if keysJSON := utils.GetEnvOr(EnvAPIKeys, ""); keysJSON != "" {
+		keysBz = []byte(keysJSON)
+	} else {
+		return nil, fmt.Errorf("missing %s or %s", EnvAPIKeysFile, EnvAPIKeys)
+	}
+
+	err = json.Unmarshal(keysBz, &cfg.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API keys: %s", err)
+	}
+
+	_, err = indexKeys(cfg.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API keys: %s", err)
+	}
+
+	return cfg, nil
+}
+
+// NewAuthenticatorFromEnvVariables returns a new Authenticator instance reading the configuration from the
+// env variables. It panics if the configuration is not valid
+func NewAuthenticatorFromEnvVariables() *Authenticator {
+	cfg, err := ReadConfigFromEnvVariables()
+	if err != nil {
+		panic(err)
+	}
+
+	ensureSecret(cfg.Sessions)
+	sessions := NewSessionsManager(cfg.Sessions)
+	if cfg.Disabled {
+		log.Warn().Msg("Authentication is disabled: all the requests will be allowed")
+		return NewDisabledAuthenticator(sessions)
+	}
+
+	authenticator, err := NewAuthenticator(cfg.Keys, sessions)
+	if err != nil {
+		panic(fmt.Errorf("invalid API keys: %s", err))
+	}
+
+	return authenticator
+}
+
+// HashKey returns the hex-encoded SHA-256 hash of the given key value
+func HashKey(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// Sessions returns the SessionsManager used to verify the wallet session tokens
+func (a *Authenticator) Sessions() *SessionsManager {
+	return a.sessions
+}
+
+// RequireScope returns a Gin handler function that makes sure the request is authenticated using an API key
+// that has been granted the given scope. If so, the identifier of the key is stored inside the context
+func (a *Authenticator) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.authenticate(c, scope, false)
+	}
+}
+
+// RequireScopeOrWallet returns a Gin handler function that makes sure the request is authenticated using either
+// an API key that has been granted the given scope, or a wallet session token. In the latter case, the address
+// of the authenticated user is stored inside the context so that the handlers can limit the operations to it
+func (a *Authenticator) RequireScopeOrWallet(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.authenticate(c, scope, true)
+	}
+}
+
+// authenticate authenticates the request associated with the given context, aborting it if it is not
+// authenticated using an API key having the given scope or, if allowWallets is true, a wallet session token
+func (a *Authenticator) authenticate(c *gin.Context, scope string, allowWallets bool) {
+	if a.disabled {
+		c.Next()
+		return
+	}
+
+	token, err := dpmutils.GetTokenValue(c)
+	if err != nil {
+		dpmutils.HandleError(c, err)
+		return
+	}
+
+	key, found := a.keys[HashKey(token)]
+	if found {
+		if !key.HasScope(scope) {
+			dpmutils.HandleError(c, dpmutils.WrapErr(http.StatusForbidden, dpmutils.ErrCodeInsufficientScope, fmt.Sprintf("API key not allowed to perform this operation: missing %s scope", scope)))
+			return
+		}
+
+		c.Set(dpmutils.APIKeyIDContextKey, key.ID)
+		c.Next()
+		return
+	}
+
+	if allowWallets {
+		address, err := a.sessions.VerifySession(token)
+		if err == nil {
+			c.Set(dpmutils.WalletAddressContextKey, address)
+			c.Next()
+			return
+		}
+	}
+
+	dpmutils.HandleError(c, dpmutils.WrapErr(http.StatusUnauthorized, dpmutils.ErrCodeUnauthorized, "invalid API key or session token"))
+}
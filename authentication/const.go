@@ -0,0 +1,12 @@
+//nolint:gosec // These are just the names of the env variables
+package authentication
+
+const (
+	EnvAPIKeys      = "API_KEYS"
+	EnvAPIKeysFile  = "API_KEYS_FILE"
+	EnvAuthDisabled = "AUTH_DISABLED"
+
+	EnvSessionSecret   = "AUTH_SESSION_SECRET"
+	EnvSessionDuration = "AUTH_SESSION_DURATION"
+	EnvNonceDuration   = "AUTH_NONCE_DURATION"
+)
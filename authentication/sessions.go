@@ -0,0 +1,169 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/desmos-labs/caerus/utils"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/desmos-labs/dpm-apis/cache"
+	dpmutils "github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	sessionIssuer = "dpm-apis"
+
+	// maxPendingNonces represents the maximum number of nonces that can be waiting to be used at the same time
+	maxPendingNonces = 100_000
+)
+
+// SessionsConfig contains the configuration used to handle the wallet sessions
+type SessionsConfig struct {
+	// Secret represents the secret used to sign the session tokens
+	Secret []byte
+
+	// SessionDuration represents how long a session token is valid
+	SessionDuration time.Duration
+
+	// NonceDuration represents how long a nonce can be used to log in
+	NonceDuration time.Duration
+}
+
+// ReadSessionsConfigFromEnvVariables returns a new SessionsConfig instance reading the values from the env variables.
+// The secret is left empty if it is not configured.
+// It returns an error if any value is not valid
+func ReadSessionsConfigFromEnvVariables() (*SessionsConfig, error) {
+	sessionDuration, err := dpmutils.ParseDurationEnvOr(EnvSessionDuration, 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceDuration, err := dpmutils.ParseDurationEnvOr(EnvNonceDuration, 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionsConfig{
+		Secret:          []byte(utils.GetEnvOr(EnvSessionSecret, "")),
+		SessionDuration: sessionDuration,
+		NonceDuration:   nonceDuration,
+	}, nil
+}
+
+// NewSessionsConfigFromEnvVariables returns a new SessionsConfig instance reading the values from the env variables.
+// If no secret is configured, a random one is generated: in this case the session tokens will not be valid
+// after a restart, nor across multiple instances of the APIs.
+// It panics if any value is not valid
+func NewSessionsConfigFromEnvVariables() *SessionsConfig {
+	cfg, err := ReadSessionsConfigFromEnvVariables()
+	if err != nil {
+		panic(err)
+	}
+
+	ensureSecret(cfg)
+	return cfg
+}
+
+// ensureSecret generates a random secret for the given configuration if it does not have any
+func ensureSecret(cfg *SessionsConfig) {
+	if len(cfg.Secret) > 0 {
+		return
+	}
+
+	log.Warn().Msgf("Missing %s: using a random secret to sign the session tokens", EnvSessionSecret)
+
+	cfg.Secret = make([]byte, 32)
+	_, err := rand.Read(cfg.Secret)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SessionsManager allows users to prove they own an address by signing a nonce with the associated key,
+// issuing short-lived session tokens to the ones that succeed
+type SessionsManager struct {
+	cfg *SessionsConfig
+
+	// nonces contains the addresses for which the pending nonces have been issued, indexed by nonce
+	nonces *cache.LRU[string, string]
+}
+
+// NewSessionsManager returns a new SessionsManager instance using the given configuration
+func NewSessionsManager(cfg *SessionsConfig) *SessionsManager {
+	return &SessionsManager{
+		cfg:    cfg,
+		nonces: cache.NewLRU[string, string](maxPendingNonces),
+	}
+}
+
+// GetLoginMessage returns the message that the user has to sign in order to log in using the given nonce
+func GetLoginMessage(nonce string) string {
+	return fmt.Sprintf("Sign this message to log in to DPM APIs. Nonce: %s", nonce)
+}
+
+// IssueNonce issues a new single-use nonce that can be signed by the owner of the given address to log in
+func (m *SessionsManager) IssueNonce(address string) (nonce string, expiresAt time.Time, err error) {
+	nonceBz := make([]byte, 32)
+	_, err = rand.Read(nonceBz)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	nonce = hex.EncodeToString(nonceBz)
+	m.nonces.Set(nonce, address, m.cfg.NonceDuration)
+
+	return nonce, time.Now().Add(m.cfg.NonceDuration), nil
+}
+
+// Login verifies that the login message built with the given nonce has been signed by the owner of the given
+// address, issuing a new session token if so. Each nonce can be used only once
+func (m *SessionsManager) Login(
+	address string, nonce string, pubKey []byte, signature []byte,
+) (token string, expiresAt time.Time, err error) {
+	nonceAddress, found := m.nonces.Pop(nonce)
+	if !found || nonceAddress != address {
+		return "", time.Time{}, fmt.Errorf("invalid or expired nonce")
+	}
+
+	err = VerifyADR036Signature(address, []byte(GetLoginMessage(nonce)), pubKey, signature)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt = time.Now().Add(m.cfg.SessionDuration)
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    sessionIssuer,
+		Subject:   address,
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString(m.cfg.Secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// VerifySession verifies the given session token, returning the address of the user owning it
+func (m *SessionsManager) VerifySession(token string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Header["alg"])
+		}
+		return m.cfg.Secret, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !claims.VerifyIssuer(sessionIssuer, true) || claims.Subject == "" {
+		return "", fmt.Errorf("invalid session token")
+	}
+
+	return claims.Subject, nil
+}
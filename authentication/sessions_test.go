@@ -0,0 +1,143 @@
+package authentication_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/desmos-labs/desmos/v6/app"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/authentication"
+)
+
+func TestSessionsTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionsTestSuite))
+}
+
+// SessionsTestSuite tests the wallet login flow, from the issuance of the nonces to the verification of the
+// session tokens
+type SessionsTestSuite struct {
+	suite.Suite
+
+	privKey  *secp256k1.PrivKey
+	address  string
+	sessions *authentication.SessionsManager
+}
+
+func (suite *SessionsTestSuite) SetupSuite() {
+	app.SetupConfig(sdk.GetConfig())
+
+	suite.privKey = secp256k1.GenPrivKey()
+	suite.address = sdk.AccAddress(suite.privKey.PubKey().Address()).String()
+}
+
+func (suite *SessionsTestSuite) SetupTest() {
+	suite.sessions = suite.newSessionsManager(time.Minute, time.Minute)
+}
+
+// newSessionsManager returns a new SessionsManager using the given session and nonce durations
+func (suite *SessionsTestSuite) newSessionsManager(sessionDuration time.Duration, nonceDuration time.Duration) *authentication.SessionsManager {
+	return authentication.NewSessionsManager(&authentication.SessionsConfig{
+		Secret:          []byte("secret"),
+		SessionDuration: sessionDuration,
+		NonceDuration:   nonceDuration,
+	})
+}
+
+// signNonce returns the signature of the login message built using the given nonce
+func (suite *SessionsTestSuite) signNonce(nonce string) []byte {
+	signBytes, err := authentication.GetADR036SignBytes(suite.address, []byte(authentication.GetLoginMessage(nonce)))
+	suite.Require().NoError(err)
+
+	signature, err := suite.privKey.Sign(signBytes)
+	suite.Require().NoError(err)
+	return signature
+}
+
+// login issues a new nonce using the given manager and logs in by signing it, returning the session token
+func (suite *SessionsTestSuite) login(sessions *authentication.SessionsManager) string {
+	nonce, _, err := sessions.IssueNonce(suite.address)
+	suite.Require().NoError(err)
+
+	token, _, err := sessions.Login(suite.address, nonce, suite.privKey.PubKey().Bytes(), suite.signNonce(nonce))
+	suite.Require().NoError(err)
+	return token
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+func (suite *SessionsTestSuite) TestLogin() {
+	token := suite.login(suite.sessions)
+
+	address, err := suite.sessions.VerifySession(token)
+	suite.Require().NoError(err)
+	suite.Require().Equal(suite.address, address)
+}
+
+func (suite *SessionsTestSuite) TestLogin_ReusedNonce() {
+	nonce, _, err := suite.sessions.IssueNonce(suite.address)
+	suite.Require().NoError(err)
+
+	signature := suite.signNonce(nonce)
+	_, _, err = suite.sessions.Login(suite.address, nonce, suite.privKey.PubKey().Bytes(), signature)
+	suite.Require().NoError(err)
+
+	_, _, err = suite.sessions.Login(suite.address, nonce, suite.privKey.PubKey().Bytes(), signature)
+	suite.Require().Error(err)
+}
+
+func (suite *SessionsTestSuite) TestLogin_TamperedNonce() {
+	nonce, _, err := suite.sessions.IssueNonce(suite.address)
+	suite.Require().NoError(err)
+
+	otherNonce, _, err := suite.sessions.IssueNonce(suite.address)
+	suite.Require().NoError(err)
+
+	// A signature of a different nonce must not be accepted
+	_, _, err = suite.sessions.Login(suite.address, nonce, suite.privKey.PubKey().Bytes(), suite.signNonce(otherNonce))
+	suite.Require().Error(err)
+
+	// A nonce that has never been issued must not be accepted, even if correctly signed
+	_, _, err = suite.sessions.Login(suite.address, "tampered", suite.privKey.PubKey().Bytes(), suite.signNonce("tampered"))
+	suite.Require().Error(err)
+}
+
+func (suite *SessionsTestSuite) TestLogin_NonceIssuedForAnotherAddress() {
+	otherAddress := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+	nonce, _, err := suite.sessions.IssueNonce(otherAddress)
+	suite.Require().NoError(err)
+
+	_, _, err = suite.sessions.Login(suite.address, nonce, suite.privKey.PubKey().Bytes(), suite.signNonce(nonce))
+	suite.Require().Error(err)
+}
+
+func (suite *SessionsTestSuite) TestLogin_ExpiredNonce() {
+	sessions := suite.newSessionsManager(time.Minute, time.Millisecond)
+	nonce, _, err := sessions.IssueNonce(suite.address)
+	suite.Require().NoError(err)
+
+	time.Sleep(10 * time.Millisecond)
+	_, _, err = sessions.Login(suite.address, nonce, suite.privKey.PubKey().Bytes(), suite.signNonce(nonce))
+	suite.Require().Error(err)
+}
+
+func (suite *SessionsTestSuite) TestVerifySession_Expired() {
+	token := suite.login(suite.newSessionsManager(-time.Minute, time.Minute))
+
+	_, err := suite.sessions.VerifySession(token)
+	suite.Require().Error(err)
+}
+
+func (suite *SessionsTestSuite) TestVerifySession_WrongSecret() {
+	sessions := authentication.NewSessionsManager(&authentication.SessionsConfig{
+		Secret:          []byte("other-secret"),
+		SessionDuration: time.Minute,
+		NonceDuration:   time.Minute,
+	})
+	token := suite.login(sessions)
+
+	_, err := suite.sessions.VerifySession(token)
+	suite.Require().Error(err)
+}
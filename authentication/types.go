@@ -0,0 +1,72 @@
+package authentication
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	// ScopeCreateLinks allows to create new deep links
+	ScopeCreateLinks = "links:create"
+
+	// ScopeReadLinks allows to read the configuration of deep links and the links created for an address
+	ScopeReadLinks = "links:read"
+
+	// ScopeAdmin allows to perform any operation
+	ScopeAdmin = "admin"
+)
+
+var (
+	keyHashRegex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+	validScopes = map[string]bool{
+		ScopeCreateLinks: true,
+		ScopeReadLinks:   true,
+		ScopeAdmin:       true,
+	}
+)
+
+// APIKey contains the data of an API key that can be used to authenticate the requests
+type APIKey struct {
+	// ID represents the public identifier of the key (e.g. the name of the partner owning it)
+	ID string `json:"id"`
+
+	// Hash represents the hex-encoded SHA-256 hash of the key value
+	Hash string `json:"hash"`
+
+	// Scopes contains the scopes granted to the key
+	Scopes []string `json:"scopes"`
+}
+
+// Validate checks whether the API key is valid or not
+func (k *APIKey) Validate() error {
+	if k.ID == "" {
+		return fmt.Errorf("missing API key id")
+	}
+
+	if !keyHashRegex.MatchString(k.Hash) {
+		return fmt.Errorf("invalid hash of API key %s: must be a lowercase hex-encoded SHA-256 hash", k.ID)
+	}
+
+	if len(k.Scopes) == 0 {
+		return fmt.Errorf("API key %s has no scopes", k.ID)
+	}
+
+	for _, scope := range k.Scopes {
+		if !validScopes[scope] {
+			return fmt.Errorf("invalid scope of API key %s: %s", k.ID, scope)
+		}
+	}
+
+	return nil
+}
+
+// HasScope tells whether the key has been granted the given scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, keyScope := range k.Scopes {
+		if keyScope == scope || keyScope == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
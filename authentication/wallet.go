@@ -0,0 +1,85 @@
+package authentication
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// adr036SignDoc represents the amino JSON sign doc used to sign arbitrary data as described inside ADR-036.
+// NOTE: The fields must be kept in alphabetical order, since the sign bytes are the sorted JSON encoding of the doc
+type adr036SignDoc struct {
+	AccountNumber string           `json:"account_number"`
+	ChainID       string           `json:"chain_id"`
+	Fee           adr036Fee        `json:"fee"`
+	Memo          string           `json:"memo"`
+	Msgs          []adr036SignData `json:"msgs"`
+	Sequence      string           `json:"sequence"`
+}
+
+type adr036Fee struct {
+	Amount []interface{} `json:"amount"`
+	Gas    string        `json:"gas"`
+}
+
+type adr036SignData struct {
+	Type  string              `json:"type"`
+	Value adr036SignDataValue `json:"value"`
+}
+
+type adr036SignDataValue struct {
+	Data   string `json:"data"`
+	Signer string `json:"signer"`
+}
+
+// GetADR036SignBytes returns the bytes that the given signer has to sign in order to sign the given data,
+// following the ADR-036 specification
+func GetADR036SignBytes(signer string, data []byte) ([]byte, error) {
+	return json.Marshal(adr036SignDoc{
+		AccountNumber: "0",
+		ChainID:       "",
+		Fee:           adr036Fee{Amount: []interface{}{}, Gas: "0"},
+		Memo:          "",
+		Msgs: []adr036SignData{{
+			Type: "sign/MsgSignData",
+			Value: adr036SignDataValue{
+				Data:   base64.StdEncoding.EncodeToString(data),
+				Signer: signer,
+			},
+		}},
+		Sequence: "0",
+	})
+}
+
+// VerifyADR036Signature makes sure that the given signature has been created by signing the given data
+// following the ADR-036 specification, using the secp256k1 private key associated with the given public key.
+// It also makes sure that the public key corresponds to the given Bech32 address
+func VerifyADR036Signature(address string, data []byte, pubKeyBz []byte, signature []byte) error {
+	accAddress, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %s", err)
+	}
+
+	if len(pubKeyBz) != secp256k1.PubKeySize {
+		return fmt.Errorf("invalid public key: must be a compressed secp256k1 public key")
+	}
+
+	pubKey := &secp256k1.PubKey{Key: pubKeyBz}
+	if !accAddress.Equals(sdk.AccAddress(pubKey.Address())) {
+		return fmt.Errorf("public key does not match the address")
+	}
+
+	signBytes, err := GetADR036SignBytes(address, data)
+	if err != nil {
+		return err
+	}
+
+	if !pubKey.VerifySignature(signBytes, signature) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package authentication_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/desmos-labs/desmos/v6/app"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/authentication"
+)
+
+func TestWalletTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletTestSuite))
+}
+
+// WalletTestSuite tests the verification of the ADR-036 signatures
+type WalletTestSuite struct {
+	suite.Suite
+
+	privKey *secp256k1.PrivKey
+	address string
+}
+
+func (suite *WalletTestSuite) SetupSuite() {
+	app.SetupConfig(sdk.GetConfig())
+
+	suite.privKey = secp256k1.GenPrivKey()
+	suite.address = sdk.AccAddress(suite.privKey.PubKey().Address()).String()
+}
+
+// sign signs the given data following the ADR-036 specification using the given key and signer address
+func (suite *WalletTestSuite) sign(privKey *secp256k1.PrivKey, signer string, data []byte) []byte {
+	signBytes, err := authentication.GetADR036SignBytes(signer, data)
+	suite.Require().NoError(err)
+
+	signature, err := privKey.Sign(signBytes)
+	suite.Require().NoError(err)
+	return signature
+}
+
+func (suite *WalletTestSuite) TestGetADR036SignBytes() {
+	signBytes, err := authentication.GetADR036SignBytes("desmos1signer", []byte("data"))
+	suite.Require().NoError(err)
+	suite.Require().Equal(
+		`{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"",`+
+			`"msgs":[{"type":"sign/MsgSignData","value":{"data":"ZGF0YQ==","signer":"desmos1signer"}}],"sequence":"0"}`,
+		string(signBytes),
+	)
+}
+
+func (suite *WalletTestSuite) TestVerifyADR036Signature() {
+	data := []byte("message")
+	otherKey := secp256k1.GenPrivKey()
+	otherAddress := sdk.AccAddress(otherKey.PubKey().Address()).String()
+
+	testCases := []struct {
+		name      string
+		address   string
+		data      []byte
+		pubKey    []byte
+		signature []byte
+		shouldErr bool
+	}{
+		{
+			name:      "valid signature",
+			address:   suite.address,
+			data:      data,
+			pubKey:    suite.privKey.PubKey().Bytes(),
+			signature: suite.sign(suite.privKey, suite.address, data),
+			shouldErr: false,
+		},
+		{
+			name:      "invalid address",
+			address:   "invalid",
+			data:      data,
+			pubKey:    suite.privKey.PubKey().Bytes(),
+			signature: suite.sign(suite.privKey, suite.address, data),
+			shouldErr: true,
+		},
+		{
+			name:      "invalid public key",
+			address:   suite.address,
+			data:      data,
+			pubKey:    []byte("invalid"),
+			signature: suite.sign(suite.privKey, suite.address, data),
+			shouldErr: true,
+		},
+		{
+			name:      "public key not matching the address",
+			address:   suite.address,
+			data:      data,
+			pubKey:    otherKey.PubKey().Bytes(),
+			signature: suite.sign(otherKey, suite.address, data),
+			shouldErr: true,
+		},
+		{
+			name:      "signature created by another key",
+			address:   suite.address,
+			data:      data,
+			pubKey:    suite.privKey.PubKey().Bytes(),
+			signature: suite.sign(otherKey, suite.address, data),
+			shouldErr: true,
+		},
+		{
+			name:      "signature created for another signer",
+			address:   suite.address,
+			data:      data,
+			pubKey:    suite.privKey.PubKey().Bytes(),
+			signature: suite.sign(suite.privKey, otherAddress, data),
+			shouldErr: true,
+		},
+		{
+			name:      "tampered data",
+			address:   suite.address,
+			data:      []byte("tampered message"),
+			pubKey:    suite.privKey.PubKey().Bytes(),
+			signature: suite.sign(suite.privKey, suite.address, data),
+			shouldErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := authentication.VerifyADR036Signature(tc.address, tc.data, tc.pubKey, tc.signature)
+			if tc.shouldErr {
+				suite.Require().Error(err)
+			} else {
+				suite.Require().NoError(err)
+			}
+		})
+	}
+}
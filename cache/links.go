@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"time"
+
+	caerustypes "github.com/desmos-labs/caerus/types"
+
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	EnvLinkConfigCacheSize        = "LINKS_CONFIG_CACHE_SIZE"
+	EnvLinkConfigCacheTTL         = "LINKS_CONFIG_CACHE_TTL"
+	EnvLinkConfigCacheNegativeTTL = "LINKS_CONFIG_CACHE_NEGATIVE_TTL"
+)
+
+// LinkConfigCacheConfig contains the configuration of a LinkConfigCache
+type LinkConfigCacheConfig struct {
+	// Size represents the maximum number of link configurations that can be cached
+	Size int
+
+	// TTL represents how long a link configuration is cached
+	TTL time.Duration
+
+	// NegativeTTL represents how long the absence of a link configuration is cached
+	NegativeTTL time.Duration
+}
+
+// DefaultLinkConfigCacheConfig returns the default LinkConfigCacheConfig instance
+func DefaultLinkConfigCacheConfig() *LinkConfigCacheConfig {
+	return &LinkConfigCacheConfig{
+		Size:        10_000,
+		TTL:         time.Hour,
+		NegativeTTL: time.Minute,
+	}
+}
+
+// ReadLinkConfigCacheConfigFromEnvVariables returns a new LinkConfigCacheConfig instance reading the values from
+// the env variables. It returns an error if any value is not valid
+func ReadLinkConfigCacheConfigFromEnvVariables() (*LinkConfigCacheConfig, error) {
+	cfg := DefaultLinkConfigCacheConfig()
+
+	var err error
+	cfg.Size, err = utils.ParsePositiveIntEnvOr(EnvLinkConfigCacheSize, cfg.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.TTL, err = utils.ParseDurationEnvOr(EnvLinkConfigCacheTTL, cfg.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.NegativeTTL, err = utils.ParseDurationEnvOr(EnvLinkConfigCacheNegativeTTL, cfg.NegativeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// NewLinkConfigCacheConfigFromEnvVariables returns a new LinkConfigCacheConfig instance reading the values from
+// the env variables. It panics if any value is not valid
+func NewLinkConfigCacheConfigFromEnvVariables() *LinkConfigCacheConfig {
+	cfg, err := ReadLinkConfigCacheConfigFromEnvVariables()
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// LinkConfigCache represents a cache that stores the link configurations by their URL.
+// Since the link configurations are immutable, they can be cached for a long time. Links that do not exist
+// are cached as well, but for a shorter time since they might be created later
+type LinkConfigCache struct {
+	cfg *LinkConfigCacheConfig
+	lru *LRU[string, *caerustypes.LinkConfig]
+}
+
+// NewLinkConfigCache returns a new LinkConfigCache instance using the given configuration
+func NewLinkConfigCache(cfg *LinkConfigCacheConfig) *LinkConfigCache {
+	return &LinkConfigCache{
+		cfg: cfg,
+		lru: NewLRU[string, *caerustypes.LinkConfig](cfg.Size),
+	}
+}
+
+// NewLinkConfigCacheFromEnvVariables returns a new LinkConfigCache instance reading the configuration from
+// the env variables
+func NewLinkConfigCacheFromEnvVariables() *LinkConfigCache {
+	return NewLinkConfigCache(NewLinkConfigCacheConfigFromEnvVariables())
+}
+
+// Get returns the configuration of the link having the given URL.
+// If found is true but the configuration is nil, it means that the link does not exist
+func (c *LinkConfigCache) Get(url string) (config *caerustypes.LinkConfig, found bool) {
+	return c.lru.Get(url)
+}
+
+// Set caches the given configuration for the link having the given URL.
+// A nil configuration means that the link does not exist
+func (c *LinkConfigCache) Set(url string, config *caerustypes.LinkConfig) {
+	ttl := c.cfg.TTL
+	if config == nil {
+		ttl = c.cfg.NegativeTTL
+	}
+	c.lru.Set(url, config, ttl)
+}
+
+// Stats returns the usage statistics of the cache
+func (c *LinkConfigCache) Stats() Stats {
+	return c.lru.Stats()
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	caerustypes "github.com/desmos-labs/caerus/types"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLinkConfigCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(LinkConfigCacheTestSuite))
+}
+
+// LinkConfigCacheTestSuite tests the caching of the link configurations
+type LinkConfigCacheTestSuite struct {
+	suite.Suite
+
+	cache *LinkConfigCache
+	now   time.Time
+}
+
+func (suite *LinkConfigCacheTestSuite) SetupTest() {
+	suite.now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.cache = NewLinkConfigCache(&LinkConfigCacheConfig{
+		Size:        10,
+		TTL:         time.Hour,
+		NegativeTTL: time.Minute,
+	})
+	suite.cache.lru.now = func() time.Time { return suite.now }
+}
+
+func (suite *LinkConfigCacheTestSuite) TestTTL() {
+	config := &caerustypes.LinkConfig{CustomData: []byte(`{"address":"desmos1"}`)}
+	suite.cache.Set("https://dpm.link/existing", config)
+	suite.cache.Set("https://dpm.link/missing", nil)
+
+	// Both the existing and the missing links should be cached
+	cached, found := suite.cache.Get("https://dpm.link/existing")
+	suite.Require().True(found)
+	suite.Require().Equal(config, cached)
+
+	cached, found = suite.cache.Get("https://dpm.link/missing")
+	suite.Require().True(found)
+	suite.Require().Nil(cached)
+
+	// Missing links should expire sooner, since they might be created later
+	suite.now = suite.now.Add(time.Minute)
+	_, found = suite.cache.Get("https://dpm.link/missing")
+	suite.Require().False(found)
+
+	_, found = suite.cache.Get("https://dpm.link/existing")
+	suite.Require().True(found)
+
+	suite.now = suite.now.Add(time.Hour)
+	_, found = suite.cache.Get("https://dpm.link/existing")
+	suite.Require().False(found)
+
+	suite.Require().Equal(Stats{Hits: 3, Misses: 2, Size: 0}, suite.cache.Stats())
+}
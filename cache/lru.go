@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats contains the usage statistics of a cache
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+// entry represents a single value stored inside the LRU cache
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// LRU represents a thread-safe, size-bounded, least recently used cache whose entries expire after a given TTL
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  *list.List
+	items    map[K]*list.Element
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+
+	// now returns the current time, and is used to compute the entries expiration
+	now func() time.Time
+}
+
+// NewLRU returns a new LRU instance that can hold up to the given number of entries
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	if capacity <= 0 {
+		panic("cache capacity must be positive")
+	}
+
+	return &LRU[K, V]{
+		capacity: capacity,
+		entries:  list.New(),
+		items:    make(map[K]*list.Element, capacity),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value associated with the given key, if it exists and has not expired yet
+func (c *LRU[K, V]) Get(key K) (value V, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		c.misses.Add(1)
+		return value, false
+	}
+
+	item := element.Value.(*entry[K, V])
+	if !c.now().Before(item.expiresAt) {
+		c.removeElement(element)
+		c.misses.Add(1)
+		return value, false
+	}
+
+	c.entries.MoveToFront(element)
+	c.hits.Add(1)
+	return item.value, true
+}
+
+// Set stores the given value associated with the given key, making it expire after the given TTL.
+// If the cache is full, the least recently used entry is evicted
+func (c *LRU[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if element, ok := c.items[key]; ok {
+		item := element.Value.(*entry[K, V])
+		item.value = value
+		item.expiresAt = expiresAt
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.entries.Len() > c.capacity {
+		c.removeElement(c.entries.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// Pop removes the value associated with the given key and returns it, if it exists and has not expired yet.
+// This allows to make sure that each value is read at most once, even when accessed concurrently
+func (c *LRU[K, V]) Pop(key K) (value V, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		c.misses.Add(1)
+		return value, false
+	}
+
+	c.removeElement(element)
+
+	item := element.Value.(*entry[K, V])
+	if !c.now().Before(item.expiresAt) {
+		c.misses.Add(1)
+		return value, false
+	}
+
+	c.hits.Add(1)
+	return item.value, true
+}
+
+// Delete removes the value associated with the given key, if any
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// removeElement removes the given element from the cache.
+// NOTE: This method must be called while holding the lock
+func (c *LRU[K, V]) removeElement(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.items, element.Value.(*entry[K, V]).key)
+}
+
+// Stats returns the usage statistics of the cache
+func (c *LRU[K, V]) Stats() Stats {
+	c.mu.Lock()
+	size := c.entries.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      size,
+	}
+}
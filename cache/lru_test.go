@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLRUTestSuite(t *testing.T) {
+	suite.Run(t, new(LRUTestSuite))
+}
+
+// LRUTestSuite tests the expiration and eviction of the LRU cache entries
+type LRUTestSuite struct {
+	suite.Suite
+
+	lru *LRU[string, int]
+	now time.Time
+}
+
+func (suite *LRUTestSuite) SetupTest() {
+	suite.now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.lru = NewLRU[string, int](2)
+	suite.lru.now = func() time.Time { return suite.now }
+}
+
+// requireFound makes sure that the given key is associated with the given value
+func (suite *LRUTestSuite) requireFound(key string, value int) {
+	cached, found := suite.lru.Get(key)
+	suite.Require().True(found, key)
+	suite.Require().Equal(value, cached)
+}
+
+// requireNotFound makes sure that the given key is not associated with any value
+func (suite *LRUTestSuite) requireNotFound(key string) {
+	_, found := suite.lru.Get(key)
+	suite.Require().False(found, key)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+func (suite *LRUTestSuite) TestExpiration() {
+	suite.lru.Set("a", 1, time.Minute)
+
+	suite.now = suite.now.Add(time.Minute - time.Nanosecond)
+	suite.requireFound("a", 1)
+
+	suite.now = suite.now.Add(time.Nanosecond)
+	suite.requireNotFound("a")
+	suite.Require().Equal(0, suite.lru.Stats().Size)
+}
+
+func (suite *LRUTestSuite) TestSet_RefreshesExistingEntry() {
+	suite.lru.Set("a", 1, time.Minute)
+	suite.now = suite.now.Add(30 * time.Second)
+	suite.lru.Set("a", 2, time.Minute)
+
+	suite.now = suite.now.Add(45 * time.Second)
+	suite.requireFound("a", 2)
+	suite.Require().Equal(1, suite.lru.Stats().Size)
+}
+
+func (suite *LRUTestSuite) TestEviction() {
+	suite.lru.Set("a", 1, time.Minute)
+	suite.lru.Set("b", 2, time.Minute)
+
+	// Reading the oldest entry makes it the most recently used one
+	suite.requireFound("a", 1)
+	suite.lru.Set("c", 3, time.Minute)
+
+	suite.requireNotFound("b")
+	suite.requireFound("a", 1)
+	suite.requireFound("c", 3)
+
+	stats := suite.lru.Stats()
+	suite.Require().Equal(uint64(1), stats.Evictions)
+	suite.Require().Equal(2, stats.Size)
+}
+
+func (suite *LRUTestSuite) TestPop() {
+	suite.lru.Set("a", 1, time.Minute)
+
+	value, found := suite.lru.Pop("a")
+	suite.Require().True(found)
+	suite.Require().Equal(1, value)
+
+	// Each value should be returned only once
+	_, found = suite.lru.Pop("a")
+	suite.Require().False(found)
+
+	// Expired values should never be returned
+	suite.lru.Set("b", 2, time.Minute)
+	suite.now = suite.now.Add(time.Minute)
+	_, found = suite.lru.Pop("b")
+	suite.Require().False(found)
+	suite.Require().Equal(0, suite.lru.Stats().Size)
+}
+
+func (suite *LRUTestSuite) TestStats() {
+	suite.lru.Set("a", 1, time.Minute)
+
+	suite.requireFound("a", 1)
+	suite.requireFound("a", 1)
+	suite.requireNotFound("b")
+
+	// Expired entries should be counted as misses
+	suite.now = suite.now.Add(time.Minute)
+	suite.requireNotFound("a")
+
+	suite.Require().Equal(Stats{Hits: 2, Misses: 2, Evictions: 0, Size: 0}, suite.lru.Stats())
+}
@@ -0,0 +1,188 @@
+package caerus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// BreakerStateClosed means that the calls are performed normally
+	BreakerStateClosed = "closed"
+
+	// BreakerStateOpen means that the calls fail immediately, without being performed
+	BreakerStateOpen = "open"
+
+	// BreakerStateHalfOpen means that a single trial call is performed to check whether Caerus has recovered
+	BreakerStateHalfOpen = "half-open"
+)
+
+// callOutcome represents how the result of a call affects the circuit breaker
+type callOutcome int
+
+const (
+	// outcomeSuccess means that Caerus handled the call
+	outcomeSuccess callOutcome = iota
+
+	// outcomeFailure means that Caerus failed to handle the call
+	outcomeFailure
+
+	// outcomeIgnored means that the call does not tell anything about the health of Caerus
+	outcomeIgnored
+)
+
+var (
+	// ErrCircuitOpen is returned when a call is not performed because the circuit breaker is open
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// CircuitBreakerConfig contains the configuration of a CircuitBreaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold represents the number of consecutive failures after which the circuit breaker opens
+	FailureThreshold int `yaml:"failure_threshold"`
+
+	// OpenDuration represents how long the circuit breaker stays open before allowing a trial call
+	OpenDuration time.Duration `yaml:"open_duration"`
+}
+
+// DefaultCircuitBreakerConfig returns the default CircuitBreakerConfig instance
+func DefaultCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+// CircuitBreaker allows to fail fast when Caerus has been failing consistently, instead of making the users wait for
+// calls that will most likely fail as well
+type CircuitBreaker struct {
+	cfg *CircuitBreakerConfig
+
+	mu                  sync.Mutex
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInFlight       bool
+
+	// now returns the current time, and is used to compute when the circuit breaker can be half-opened
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a new CircuitBreaker instance using the given configuration
+func NewCircuitBreaker(cfg *CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		cfg:   cfg,
+		state: BreakerStateClosed,
+		now:   time.Now,
+	}
+}
+
+// Execute performs the given call if the circuit breaker allows it, returning ErrCircuitOpen otherwise.
+// The given context must be the one of the caller: calls that fail because it is done are not recorded, since they
+// do not tell anything about the health of Caerus
+func (b *CircuitBreaker) Execute(ctx context.Context, call func() error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+
+	err := call()
+	b.record(getOutcome(ctx, err))
+	return err
+}
+
+// State returns the current state of the circuit breaker
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerStateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenDuration {
+		return BreakerStateHalfOpen
+	}
+	return b.state
+}
+
+// allow tells whether a call can be performed
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerStateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenDuration {
+		b.state = BreakerStateHalfOpen
+	}
+
+	switch b.state {
+	case BreakerStateClosed:
+		return true
+
+	case BreakerStateHalfOpen:
+		// Allow a single trial call at a time
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+
+	default:
+		return false
+	}
+}
+
+// record records the outcome of a call
+func (b *CircuitBreaker) record(outcome callOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasHalfOpen := b.state == BreakerStateHalfOpen
+	b.trialInFlight = false
+
+	switch outcome {
+	case outcomeIgnored:
+		// Keep the current state, so that another trial call is allowed if this one was the trial
+		return
+
+	case outcomeSuccess:
+		if b.state != BreakerStateClosed {
+			log.Info().Msg("Caerus recovered, closing the circuit breaker")
+		}
+		b.state = BreakerStateClosed
+		b.consecutiveFailures = 0
+		return
+	}
+
+	b.consecutiveFailures++
+	if wasHalfOpen || b.consecutiveFailures >= b.cfg.FailureThreshold {
+		if b.state != BreakerStateOpen {
+			log.Warn().Int("failures", b.consecutiveFailures).Msg("Caerus is failing, opening the circuit breaker")
+		}
+		b.state = BreakerStateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// getOutcome returns the outcome of a call performed within the given caller context that returned the given error.
+// Calls that fail because the caller went away or its own deadline expired are ignored, even if they are reported as
+// timeouts, so that a few slow clients cannot open the circuit breaker for everyone. Errors caused by invalid requests
+// are considered successes, since Caerus handled them
+func getOutcome(ctx context.Context, err error) callOutcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+
+	if ctx.Err() != nil {
+		return outcomeIgnored
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.DataLoss:
+		return outcomeFailure
+	case codes.Canceled:
+		return outcomeIgnored
+	default:
+		return outcomeSuccess
+	}
+}
@@ -0,0 +1,175 @@
+package caerus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnavailable = status.Error(codes.Unavailable, "unavailable")
+)
+
+func TestCircuitBreakerTestSuite(t *testing.T) {
+	suite.Run(t, new(CircuitBreakerTestSuite))
+}
+
+// CircuitBreakerTestSuite tests the transitions between the circuit breaker states
+type CircuitBreakerTestSuite struct {
+	suite.Suite
+
+	breaker *CircuitBreaker
+	now     time.Time
+}
+
+func (suite *CircuitBreakerTestSuite) SetupTest() {
+	suite.now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.breaker = NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold: 3,
+		OpenDuration:     time.Minute,
+	})
+	suite.breaker.now = func() time.Time { return suite.now }
+}
+
+// fail performs the given number of failing calls
+func (suite *CircuitBreakerTestSuite) fail(calls int) {
+	for i := 0; i < calls; i++ {
+		suite.Require().Equal(errUnavailable, suite.breaker.Execute(context.Background(), func() error { return errUnavailable }))
+	}
+}
+
+// open opens the circuit breaker and waits until it can be half-opened
+func (suite *CircuitBreakerTestSuite) open() {
+	suite.fail(3)
+	suite.Require().Equal(BreakerStateOpen, suite.breaker.State())
+	suite.now = suite.now.Add(time.Minute)
+	suite.Require().Equal(BreakerStateHalfOpen, suite.breaker.State())
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+func (suite *CircuitBreakerTestSuite) TestOpensAtThreshold() {
+	suite.fail(2)
+	suite.Require().Equal(BreakerStateClosed, suite.breaker.State())
+
+	suite.fail(1)
+	suite.Require().Equal(BreakerStateOpen, suite.breaker.State())
+
+	called := false
+	err := suite.breaker.Execute(context.Background(), func() error {
+		called = true
+		return nil
+	})
+	suite.Require().ErrorIs(err, ErrCircuitOpen)
+	suite.Require().False(called)
+}
+
+func (suite *CircuitBreakerTestSuite) TestSuccessResetsFailures() {
+	suite.fail(2)
+	suite.Require().NoError(suite.breaker.Execute(context.Background(), func() error { return nil }))
+	suite.fail(2)
+	suite.Require().Equal(BreakerStateClosed, suite.breaker.State())
+}
+
+func (suite *CircuitBreakerTestSuite) TestIgnoresClientErrors() {
+	for i := 0; i < 5; i++ {
+		err := suite.breaker.Execute(context.Background(), func() error { return status.Error(codes.InvalidArgument, "invalid") })
+		suite.Require().Error(err)
+	}
+	suite.Require().Equal(BreakerStateClosed, suite.breaker.State())
+}
+
+func (suite *CircuitBreakerTestSuite) TestStaysOpenBeforeDuration() {
+	suite.fail(3)
+	suite.now = suite.now.Add(time.Minute - time.Second)
+	suite.Require().Equal(BreakerStateOpen, suite.breaker.State())
+	suite.Require().ErrorIs(suite.breaker.Execute(context.Background(), func() error { return nil }), ErrCircuitOpen)
+}
+
+func (suite *CircuitBreakerTestSuite) TestHalfOpenAllowsSingleTrial() {
+	suite.open()
+
+	err := suite.breaker.Execute(context.Background(), func() error {
+		// Concurrent calls must be rejected while the trial call is in flight
+		suite.Require().ErrorIs(suite.breaker.Execute(context.Background(), func() error { return nil }), ErrCircuitOpen)
+		return nil
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *CircuitBreakerTestSuite) TestHalfOpenTrialSuccess() {
+	suite.open()
+
+	suite.Require().NoError(suite.breaker.Execute(context.Background(), func() error { return nil }))
+	suite.Require().Equal(BreakerStateClosed, suite.breaker.State())
+
+	// The failures count must restart from zero after closing
+	suite.fail(2)
+	suite.Require().Equal(BreakerStateClosed, suite.breaker.State())
+}
+
+func (suite *CircuitBreakerTestSuite) TestHalfOpenTrialFailure() {
+	suite.open()
+
+	// A single failure must re-open the circuit breaker for the whole duration
+	suite.fail(1)
+	suite.Require().Equal(BreakerStateOpen, suite.breaker.State())
+
+	suite.now = suite.now.Add(time.Minute - time.Second)
+	suite.Require().ErrorIs(suite.breaker.Execute(context.Background(), func() error { return nil }), ErrCircuitOpen)
+
+	suite.now = suite.now.Add(time.Second)
+	suite.Require().Equal(BreakerStateHalfOpen, suite.breaker.State())
+}
+
+func (suite *CircuitBreakerTestSuite) TestUpstreamTimeouts() {
+	for i := 0; i < 3; i++ {
+		err := suite.breaker.Execute(context.Background(), func() error {
+			return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+		})
+		suite.Require().Error(err)
+	}
+	suite.Require().Equal(BreakerStateOpen, suite.breaker.State())
+}
+
+func (suite *CircuitBreakerTestSuite) TestIgnoresCallerTimeouts() {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	// Timeouts caused by the own deadline of the caller should not count as failures
+	for i := 0; i < 5; i++ {
+		err := suite.breaker.Execute(ctx, func() error {
+			return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+		})
+		suite.Require().Error(err)
+	}
+	suite.Require().Equal(BreakerStateClosed, suite.breaker.State())
+
+	// They should not reset the failures count either
+	suite.fail(2)
+	suite.Require().Error(suite.breaker.Execute(ctx, func() error {
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}))
+	suite.fail(1)
+	suite.Require().Equal(BreakerStateOpen, suite.breaker.State())
+}
+
+func (suite *CircuitBreakerTestSuite) TestHalfOpenTrialCanceled() {
+	suite.open()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := suite.breaker.Execute(ctx, func() error {
+		cancel()
+		return status.Error(codes.Canceled, "context canceled")
+	})
+	suite.Require().Error(err)
+
+	// The canceled trial should neither close nor re-open the circuit breaker, allowing another trial
+	suite.Require().Equal(BreakerStateHalfOpen, suite.breaker.State())
+	suite.Require().NoError(suite.breaker.Execute(context.Background(), func() error { return nil }))
+	suite.Require().Equal(BreakerStateClosed, suite.breaker.State())
+}
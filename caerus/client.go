@@ -5,16 +5,31 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	caerusauth "github.com/desmos-labs/caerus/authentication"
 	caeruslinks "github.com/desmos-labs/caerus/routes/links"
 	caerustypes "github.com/desmos-labs/caerus/types"
-	"github.com/desmos-labs/caerus/utils"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+
+	"github.com/desmos-labs/dpm-apis/caerus/fake"
+	"github.com/desmos-labs/dpm-apis/metrics"
+	dpmutils "github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	// RequestIDMetadataKey represents the gRPC metadata key used to forward the identifier of the request being
+	// handled to Caerus, so that the calls can be correlated with the requests that caused them
+	RequestIDMetadataKey = "x-request-id"
 )
 
 var (
@@ -23,93 +38,211 @@ var (
 
 // Client represents a client that can be used to communicate with the Caerus server
 type Client struct {
-	branchKey    string
-	caerusApiKey string
+	cfg          *Config
+	breaker      *CircuitBreaker
+	grpcConn     *grpc.ClientConn
 	linksService caeruslinks.LinksServiceClient
 }
 
-// NewClient returns a new Client instance with the given gRPC connection
-func NewClient(branchKey string, caerusApiKey string, caerusGrpcConn *grpc.ClientConn) *Client {
+// NewClient returns a new Client instance with the given configuration and gRPC connection.
+// Each call performed to Caerus is canceled if it does not complete within the configured timeout
+func NewClient(cfg *Config, caerusGrpcConn *grpc.ClientConn) *Client {
 	return &Client{
-		branchKey:    branchKey,
-		caerusApiKey: caerusApiKey,
+		cfg:          cfg,
+		breaker:      NewCircuitBreaker(cfg.CircuitBreaker),
+		grpcConn:     caerusGrpcConn,
 		linksService: caeruslinks.NewLinksServiceClient(caerusGrpcConn),
 	}
 }
 
-// NewClientFromEnvVariables returns a new Client instance from the environment variables
-func NewClientFromEnvVariables() *Client {
-	branchApiKey := utils.GetEnvOr(EnvBranchKey, "")
-	if branchApiKey == "" {
-		panic(fmt.Errorf("missing %s", EnvBranchKey))
-	}
-
-	caerusGrpcAddress := utils.GetEnvOr(EnvCaerusGRPCAddress, "")
-	if caerusGrpcAddress == "" {
-		panic(fmt.Errorf("missing %s", EnvCaerusGRPCAddress))
-	}
-
-	caerusApiKey := utils.GetEnvOr(EnvCaerusAPIKey, "")
-	if caerusApiKey == "" {
-		panic(fmt.Errorf("missing %s", EnvCaerusAPIKey))
+// NewClientFromConfig returns a new Client instance connected to the Caerus instance specified inside the given
+// configuration, or a mock one if the configuration uses ModeMock
+func NewClientFromConfig(cfg *Config) *Client {
+	if cfg.Mode == ModeMock {
+		return NewMockClient(cfg)
 	}
 
 	// Build the transport credentials based on the HTTP protocol specified inside the URL
 	transportCredential := insecure.NewCredentials()
-	if strings.HasPrefix(caerusGrpcAddress, "https://") {
+	if strings.HasPrefix(cfg.GRPCAddress, "https://") {
 		transportCredential = credentials.NewClientTLSFromCert(nil, "")
 	}
 
 	// Trim the https?:// prefix
-	caerusGrpcAddress = addressPrefix.ReplaceAllString(caerusGrpcAddress, "")
+	caerusGrpcAddress := addressPrefix.ReplaceAllString(cfg.GRPCAddress, "")
 
 	// Build the connection
-	grpcConn, err := grpc.Dial(caerusGrpcAddress, grpc.WithTransportCredentials(transportCredential))
+	grpcConn, err := grpc.Dial(
+		caerusGrpcAddress,
+		grpc.WithTransportCredentials(transportCredential),
+		grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
 	if err != nil {
 		panic(err)
 	}
 
-	return NewClient(branchApiKey, caerusApiKey, grpcConn)
+	return NewClient(cfg, grpcConn)
 }
 
-func (client *Client) getContext() context.Context {
-	return caerusauth.SetupContextWithAuthorization(context.Background(), client.caerusApiKey)
+// NewMockClient returns a new Client instance whose calls are handled by an in-process fake Caerus server.
+// The created deep links are fake and cannot be opened, but their configurations are kept in memory so that they
+// can be retrieved later
+func NewMockClient(cfg *Config) *Client {
+	log.Warn().Str("base_url", fake.DefaultBaseURL).
+		Msg("Caerus mock mode enabled: deep links are NOT created on Branch and are lost upon restart")
+
+	// The fake server requires a Branch key to create custom links, even if it is not used
+	if cfg.BranchKey == "" {
+		mockCfg := *cfg
+		mockCfg.BranchKey = ModeMock
+		cfg = &mockCfg
+	}
+
+	grpcConn, err := fake.NewServer(cfg.APIKey, fake.DefaultBaseURL).ServeInMemory(
+		grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return NewClient(cfg, grpcConn)
+}
+
+// getContext returns a new context, derived from the given one, containing the authorization data of the client
+// and the identifier of the request being handled, if any.
+// The returned context expires after the RPC timeout, and must be canceled once the call has completed
+func (client *Client) getContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, client.cfg.RPCTimeout)
+	if requestID := dpmutils.RequestIDFromContext(ctx); requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, requestID)
+	}
+	return caerusauth.SetupContextWithAuthorization(ctx, client.cfg.APIKey), cancel
 }
 
 // --------------------------------------------------------------------------------------------------------------------
 
+// CheckHealth checks whether the Caerus instance can be reached, returning an error if it cannot.
+// It waits for the gRPC connection to be ready and, if Caerus exposes the standard gRPC health service,
+// makes sure that it is serving
+func (client *Client) CheckHealth(ctx context.Context) error {
+	// Wait for the connection to be established, since idle connections are only established upon the first call
+	for state := client.grpcConn.GetState(); state != connectivity.Ready; state = client.grpcConn.GetState() {
+		if state == connectivity.Shutdown {
+			return fmt.Errorf("connection is closed")
+		}
+
+		client.grpcConn.Connect()
+		if !client.grpcConn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection not ready: %s", strings.ToLower(state.String()))
+		}
+	}
+
+	ctx, cancel := client.getContext(ctx)
+	defer cancel()
+
+	res, err := healthpb.NewHealthClient(client.grpcConn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		// The health service is optional, so the connection being ready is enough if it is not available
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		return err
+	}
+
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("not serving: %s", strings.ToLower(res.Status.String()))
+	}
+
+	return nil
+}
+
+// CircuitBreakerState returns the current state of the circuit breaker protecting the calls to Caerus
+func (client *Client) CircuitBreakerState() string {
+	return client.breaker.State()
+}
+
+// RPCTimeout returns the maximum duration of each call performed to Caerus
+func (client *Client) RPCTimeout() time.Duration {
+	return client.cfg.RPCTimeout
+}
+
+// call performs the given call if allowed by the circuit breaker, providing it a context that contains the
+// authorization data and expires after the RPC timeout
+func (client *Client) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	return client.breaker.Execute(ctx, func() error {
+		callCtx, cancel := client.getContext(ctx)
+		defer cancel()
+
+		return fn(callCtx)
+	})
+}
+
+// callIdempotent performs the given idempotent call like call does, retrying it if it fails with a transient error
+func (client *Client) callIdempotent(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withRetry(ctx, client.cfg.Retry, func() error {
+		return client.call(ctx, fn)
+	})
+}
+
 // CreateAddressLink allows to generate a new deep link that allows to open the given address on the given
 // chain and perform the action decided by the user
-func (client *Client) CreateAddressLink(request *caeruslinks.CreateAddressLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
-	return client.linksService.CreateAddressLink(client.getContext(), request)
+func (client *Client) CreateAddressLink(ctx context.Context, request *caeruslinks.CreateAddressLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	var res *caeruslinks.CreateLinkResponse
+	err := client.call(ctx, func(ctx context.Context) (err error) {
+		res, err = client.linksService.CreateAddressLink(ctx, request)
+		return err
+	})
+	return res, convertErr(err)
 }
 
 // CreateViewProfileLink allows to generate a new deep link that allows to view the profile of the given user
-func (client *Client) CreateViewProfileLink(request *caeruslinks.CreateViewProfileLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
-	return client.linksService.CreateViewProfileLink(client.getContext(), request)
+func (client *Client) CreateViewProfileLink(ctx context.Context, request *caeruslinks.CreateViewProfileLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	var res *caeruslinks.CreateLinkResponse
+	err := client.call(ctx, func(ctx context.Context) (err error) {
+		res, err = client.linksService.CreateViewProfileLink(ctx, request)
+		return err
+	})
+	return res, convertErr(err)
 }
 
 // CreateSendLink allows to generate a new deep link that allows to send tokens to the given address
-func (client *Client) CreateSendLink(request *caeruslinks.CreateSendLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
-	return client.linksService.CreateSendLink(client.getContext(), request)
+func (client *Client) CreateSendLink(ctx context.Context, request *caeruslinks.CreateSendLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	var res *caeruslinks.CreateLinkResponse
+	err := client.call(ctx, func(ctx context.Context) (err error) {
+		res, err = client.linksService.CreateSendLink(ctx, request)
+		return err
+	})
+	return res, convertErr(err)
 }
 
 // CreateLink allows to generated a new deep link based on the given configuration
-func (client *Client) CreateLink(config *caerustypes.LinkConfig) (*caeruslinks.CreateLinkResponse, error) {
-	return client.linksService.CreateLink(client.getContext(), &caeruslinks.CreateLinkRequest{
-		LinkConfiguration: config,
-		ApiKey:            client.branchKey,
+func (client *Client) CreateLink(ctx context.Context, config *caerustypes.LinkConfig) (*caeruslinks.CreateLinkResponse, error) {
+	var res *caeruslinks.CreateLinkResponse
+	err := client.call(ctx, func(ctx context.Context) (err error) {
+		res, err = client.linksService.CreateLink(ctx, &caeruslinks.CreateLinkRequest{
+			LinkConfiguration: config,
+			ApiKey:            client.cfg.BranchKey,
+		})
+		return err
 	})
+	return res, convertErr(err)
 }
 
-// GetLinkConfig allows to get the configuration used to generate a link
-func (client *Client) GetLinkConfig(url string) (*caerustypes.LinkConfig, error) {
-	res, err := client.linksService.GetLinkConfig(client.getContext(), &caeruslinks.GetLinkConfigRequest{Url: url})
+// GetLinkConfig allows to get the configuration used to generate a link.
+// Since this call is idempotent, it is retried if it fails with a transient error
+func (client *Client) GetLinkConfig(ctx context.Context, url string) (*caerustypes.LinkConfig, error) {
+	var res *caerustypes.LinkConfig
+	err := client.callIdempotent(ctx, func(ctx context.Context) (err error) {
+		res, err = client.linksService.GetLinkConfig(ctx, &caeruslinks.GetLinkConfigRequest{Url: url})
+		return err
+	})
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			return nil, nil
 		}
-		return nil, err
+		return nil, convertErr(err)
 	}
 	return res, nil
 }
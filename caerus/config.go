@@ -0,0 +1,106 @@
+package caerus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// ModeGRPC represents the mode in which the calls are sent to a real Caerus instance
+	ModeGRPC = "grpc"
+
+	// ModeMock represents the mode in which the calls are handled in-process by a fake Caerus server, generating
+	// fake deep links. It allows running the APIs without any Caerus instance nor Branch key
+	ModeMock = "mock"
+)
+
+// Config contains the configuration of a Client
+type Config struct {
+	// Mode tells whether the calls should be sent to a real Caerus instance (ModeGRPC) or handled in-process (ModeMock)
+	Mode string `yaml:"mode"`
+
+	// GRPCAddress represents the address of the Caerus instance (e.g. https://grpc-caerus.mainnet.desmos.network:443)
+	GRPCAddress string `yaml:"grpc_address"`
+
+	// APIKey represents the key used to authenticate the application inside Caerus
+	APIKey string `yaml:"api_key"`
+
+	// BranchKey represents the Branch.io key used to create custom deep links
+	BranchKey string `yaml:"branch_key"`
+
+	// RPCTimeout represents the maximum duration of each call performed to Caerus
+	RPCTimeout time.Duration `yaml:"rpc_timeout"`
+
+	// Retry contains the configuration used to retry the idempotent calls
+	Retry *RetryConfig `yaml:"retry"`
+
+	// CircuitBreaker contains the configuration of the circuit breaker protecting all the calls
+	CircuitBreaker *CircuitBreakerConfig `yaml:"circuit_breaker"`
+}
+
+// DefaultConfig returns the default Config instance, which does not contain any address or key
+func DefaultConfig() *Config {
+	return &Config{
+		Mode:           ModeGRPC,
+		RPCTimeout:     10 * time.Second,
+		Retry:          DefaultRetryConfig(),
+		CircuitBreaker: DefaultCircuitBreakerConfig(),
+	}
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found.
+// When using ModeMock, the address and keys are not required
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.Mode {
+	case ModeGRPC:
+		if c.GRPCAddress == "" {
+			errs = append(errs, fmt.Errorf("missing grpc_address (%s)", EnvCaerusGRPCAddress))
+		}
+		if c.APIKey == "" {
+			errs = append(errs, fmt.Errorf("missing api_key (%s)", EnvCaerusAPIKey))
+		}
+		if c.BranchKey == "" {
+			errs = append(errs, fmt.Errorf("missing branch_key (%s)", EnvBranchKey))
+		}
+
+	case ModeMock:
+		break
+
+	default:
+		errs = append(errs, fmt.Errorf("invalid mode %q: must be either %s or %s", c.Mode, ModeGRPC, ModeMock))
+	}
+
+	if c.RPCTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("invalid rpc_timeout: must be a positive duration"))
+	}
+
+	if c.Retry == nil {
+		errs = append(errs, fmt.Errorf("missing retry"))
+	} else {
+		if c.Retry.MaxAttempts <= 0 {
+			errs = append(errs, fmt.Errorf("invalid retry.max_attempts: must be a positive integer"))
+		}
+		if c.Retry.InitialBackoff <= 0 {
+			errs = append(errs, fmt.Errorf("invalid retry.initial_backoff: must be a positive duration"))
+		}
+		if c.Retry.MaxBackoff < c.Retry.InitialBackoff {
+			errs = append(errs, fmt.Errorf("invalid retry.max_backoff: must not be lower than initial_backoff"))
+		}
+	}
+
+	if c.CircuitBreaker == nil {
+		errs = append(errs, fmt.Errorf("missing circuit_breaker"))
+	} else {
+		if c.CircuitBreaker.FailureThreshold <= 0 {
+			errs = append(errs, fmt.Errorf("invalid circuit_breaker.failure_threshold: must be a positive integer"))
+		}
+		if c.CircuitBreaker.OpenDuration <= 0 {
+			errs = append(errs, fmt.Errorf("invalid circuit_breaker.open_duration: must be a positive duration"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -2,7 +2,16 @@
 package caerus
 
 const (
+	EnvCaerusMode        = "CAERUS_MODE"
 	EnvCaerusGRPCAddress = "CAERUS_GRPC_ADDRESS"
 	EnvCaerusAPIKey      = "CAERUS_API_KEY"
 	EnvBranchKey         = "BRANCH_KEY"
+	EnvCaerusRPCTimeout  = "CAERUS_RPC_TIMEOUT"
+
+	EnvCaerusRetryMaxAttempts    = "CAERUS_RETRY_MAX_ATTEMPTS"
+	EnvCaerusRetryInitialBackoff = "CAERUS_RETRY_INITIAL_BACKOFF"
+	EnvCaerusRetryMaxBackoff     = "CAERUS_RETRY_MAX_BACKOFF"
+
+	EnvCaerusBreakerFailureThreshold = "CAERUS_BREAKER_FAILURE_THRESHOLD"
+	EnvCaerusBreakerOpenDuration     = "CAERUS_BREAKER_OPEN_DURATION"
 )
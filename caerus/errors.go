@@ -0,0 +1,65 @@
+package caerus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	dpmutils "github.com/desmos-labs/dpm-apis/utils"
+)
+
+// convertErr converts the given error returned by a Caerus call into an error that can be returned to the user,
+// mapping the gRPC status code to the most appropriate HTTP status code.
+// The messages returned by Caerus are exposed only for the errors caused by the user requests, while the details of
+// all other errors are hidden from the user and only kept as the cause of the returned error so that they are logged
+func convertErr(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return dpmutils.WrapErr(http.StatusServiceUnavailable, dpmutils.ErrCodeUpstreamUnavailable, "Caerus is currently unavailable, please retry later").WithCause(err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return dpmutils.WrapErr(http.StatusGatewayTimeout, dpmutils.ErrCodeUpstreamTimeout, "Caerus request timed out").WithCause(err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return dpmutils.WrapErr(dpmutils.StatusClientClosedRequest, dpmutils.ErrCodeRequestCanceled, "request canceled").WithCause(err)
+	}
+
+	grpcStatus := status.Convert(err)
+	switch grpcStatus.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return dpmutils.WrapErr(http.StatusBadRequest, dpmutils.ErrCodeBadRequest, grpcStatus.Message()).WithCause(err)
+
+	case codes.NotFound:
+		return dpmutils.WrapErr(http.StatusNotFound, dpmutils.ErrCodeNotFound, grpcStatus.Message()).WithCause(err)
+
+	case codes.AlreadyExists:
+		return dpmutils.WrapErr(http.StatusConflict, dpmutils.ErrCodeConflict, grpcStatus.Message()).WithCause(err)
+
+	case codes.ResourceExhausted:
+		return dpmutils.WrapErr(http.StatusTooManyRequests, dpmutils.ErrCodeUpstreamRateLimited, "too many requests, please retry later").WithCause(err)
+
+	case codes.Unauthenticated, codes.PermissionDenied:
+		// These errors are caused by our own Caerus API key, so they must be fixed by us rather than the user
+		log.Error().Err(err).Bool("caerus_api_key_rejected", true).Msg("Caerus rejected the configured API key")
+		return dpmutils.WrapErr(http.StatusBadGateway, dpmutils.ErrCodeUpstreamError, "upstream service error").WithCause(err)
+
+	case codes.Unavailable:
+		return dpmutils.WrapErr(http.StatusServiceUnavailable, dpmutils.ErrCodeUpstreamUnavailable, "upstream service unavailable, please retry later").WithCause(err)
+
+	case codes.DeadlineExceeded:
+		return dpmutils.WrapErr(http.StatusGatewayTimeout, dpmutils.ErrCodeUpstreamTimeout, "Caerus request timed out").WithCause(err)
+
+	case codes.Canceled:
+		return dpmutils.WrapErr(dpmutils.StatusClientClosedRequest, dpmutils.ErrCodeRequestCanceled, "request canceled").WithCause(err)
+
+	default:
+		return dpmutils.WrapErr(http.StatusBadGateway, dpmutils.ErrCodeUpstreamError, "upstream service error").WithCause(err)
+	}
+}
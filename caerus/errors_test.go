@@ -0,0 +1,181 @@
+package caerus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	dpmutils "github.com/desmos-labs/dpm-apis/utils"
+)
+
+func TestConvertErrTestSuite(t *testing.T) {
+	suite.Run(t, new(ConvertErrTestSuite))
+}
+
+// ConvertErrTestSuite tests the conversion of the errors returned by Caerus into the ones returned to the users
+type ConvertErrTestSuite struct {
+	suite.Suite
+
+	logs   *bytes.Buffer
+	logger zerolog.Logger
+}
+
+func (suite *ConvertErrTestSuite) SetupTest() {
+	suite.logs = &bytes.Buffer{}
+	suite.logger = log.Logger
+	log.Logger = zerolog.New(suite.logs)
+}
+
+func (suite *ConvertErrTestSuite) TearDownTest() {
+	log.Logger = suite.logger
+}
+
+func (suite *ConvertErrTestSuite) TestConvertErr() {
+	testCases := []struct {
+		name            string
+		err             error
+		expStatus       int
+		expCode         string
+		expMessage      string
+		expKeyRejection bool
+	}{
+		{
+			name:       "invalid argument exposes the Caerus message",
+			err:        status.Error(codes.InvalidArgument, "invalid address"),
+			expStatus:  http.StatusBadRequest,
+			expCode:    dpmutils.ErrCodeBadRequest,
+			expMessage: "invalid address",
+		},
+		{
+			name:       "failed precondition is a bad request",
+			err:        status.Error(codes.FailedPrecondition, "missing branch key"),
+			expStatus:  http.StatusBadRequest,
+			expCode:    dpmutils.ErrCodeBadRequest,
+			expMessage: "missing branch key",
+		},
+		{
+			name:       "not found",
+			err:        status.Error(codes.NotFound, "link not found"),
+			expStatus:  http.StatusNotFound,
+			expCode:    dpmutils.ErrCodeNotFound,
+			expMessage: "link not found",
+		},
+		{
+			name:       "already exists",
+			err:        status.Error(codes.AlreadyExists, "link already exists"),
+			expStatus:  http.StatusConflict,
+			expCode:    dpmutils.ErrCodeConflict,
+			expMessage: "link already exists",
+		},
+		{
+			name:      "resource exhausted",
+			err:       status.Error(codes.ResourceExhausted, "quota exceeded"),
+			expStatus: http.StatusTooManyRequests,
+			expCode:   dpmutils.ErrCodeUpstreamRateLimited,
+		},
+		{
+			name:            "unauthenticated hides the cause and flags the API key",
+			err:             status.Error(codes.Unauthenticated, "invalid token"),
+			expStatus:       http.StatusBadGateway,
+			expCode:         dpmutils.ErrCodeUpstreamError,
+			expMessage:      "upstream service error",
+			expKeyRejection: true,
+		},
+		{
+			name:            "permission denied hides the cause and flags the API key",
+			err:             status.Error(codes.PermissionDenied, "forbidden"),
+			expStatus:       http.StatusBadGateway,
+			expCode:         dpmutils.ErrCodeUpstreamError,
+			expMessage:      "upstream service error",
+			expKeyRejection: true,
+		},
+		{
+			name:      "unavailable",
+			err:       status.Error(codes.Unavailable, "connection refused"),
+			expStatus: http.StatusServiceUnavailable,
+			expCode:   dpmutils.ErrCodeUpstreamUnavailable,
+		},
+		{
+			name:      "upstream deadline exceeded",
+			err:       status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			expStatus: http.StatusGatewayTimeout,
+			expCode:   dpmutils.ErrCodeUpstreamTimeout,
+		},
+		{
+			name:      "upstream canceled",
+			err:       status.Error(codes.Canceled, "canceled"),
+			expStatus: dpmutils.StatusClientClosedRequest,
+			expCode:   dpmutils.ErrCodeRequestCanceled,
+		},
+		{
+			name:       "internal errors hide the cause",
+			err:        status.Error(codes.Internal, "database password is wrong"),
+			expStatus:  http.StatusBadGateway,
+			expCode:    dpmutils.ErrCodeUpstreamError,
+			expMessage: "upstream service error",
+		},
+		{
+			name:      "non-gRPC errors are upstream errors",
+			err:       errors.New("unexpected"),
+			expStatus: http.StatusBadGateway,
+			expCode:   dpmutils.ErrCodeUpstreamError,
+		},
+		{
+			name:      "open circuit breaker",
+			err:       fmt.Errorf("wrapped: %w", ErrCircuitOpen),
+			expStatus: http.StatusServiceUnavailable,
+			expCode:   dpmutils.ErrCodeUpstreamUnavailable,
+		},
+		{
+			name:      "wrapped context deadline",
+			err:       fmt.Errorf("wrapped: %w", context.DeadlineExceeded),
+			expStatus: http.StatusGatewayTimeout,
+			expCode:   dpmutils.ErrCodeUpstreamTimeout,
+		},
+		{
+			name:      "wrapped context cancellation",
+			err:       fmt.Errorf("wrapped: %w", context.Canceled),
+			expStatus: dpmutils.StatusClientClosedRequest,
+			expCode:   dpmutils.ErrCodeRequestCanceled,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			suite.logs.Reset()
+
+			err := convertErr(tc.err)
+
+			var httpErr *dpmutils.HttpError
+			suite.Require().True(errors.As(err, &httpErr))
+			suite.Require().Equal(tc.expStatus, httpErr.StatusCode)
+			suite.Require().Equal(tc.expCode, httpErr.Code)
+			if tc.expMessage != "" {
+				suite.Require().Equal(tc.expMessage, httpErr.Message)
+			}
+
+			// The original error should always be kept as the cause, so that it is logged
+			suite.Require().ErrorIs(err, tc.err)
+
+			if tc.expKeyRejection {
+				suite.Require().Contains(suite.logs.String(), `"caerus_api_key_rejected":true`)
+			} else {
+				suite.Require().NotContains(suite.logs.String(), "caerus_api_key_rejected")
+			}
+		})
+	}
+}
+
+func (suite *ConvertErrTestSuite) TestConvertErr_Nil() {
+	suite.Require().NoError(convertErr(nil))
+}
@@ -0,0 +1,146 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	caeruslinks "github.com/desmos-labs/caerus/routes/links"
+	caeruserrors "github.com/desmos-labs/caerus/server/errors"
+	caerustypes "github.com/desmos-labs/caerus/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	// DefaultBaseURL represents the base URL used by default to generate the links URLs
+	DefaultBaseURL = "https://dpm.fake.link"
+
+	// appID represents the identifier of the application on behalf of which all the links are created
+	appID = "dpm-apis"
+
+	// bufconnSize represents the size of the buffer used by the in-memory connections
+	bufconnSize = 1024 * 1024
+)
+
+var (
+	_ caeruslinks.LinksServiceServer = &Server{}
+)
+
+// Server represents a fake Caerus gRPC server that can be used to run the APIs without a real Caerus instance nor
+// any Branch key. It exposes the links service and the standard health service, keeping the configurations of the
+// created links in memory and generating deterministic URLs for them.
+// The links configurations are built the same way Caerus builds them, so that they can be used to check the
+// requests sent by the APIs
+type Server struct {
+	apiKey     string
+	handler    *caeruslinks.Handler
+	grpcServer *grpc.Server
+}
+
+// NewServer returns a new Server instance that accepts only the calls authorized using the given API key,
+// and generates the links URLs using the given base URL
+func NewServer(apiKey string, baseURL string) *Server {
+	linksStore := newStore(baseURL)
+	server := &Server{
+		apiKey:  apiKey,
+		handler: caeruslinks.NewHandler(linksStore, linksStore),
+	}
+
+	server.grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.authenticate, caeruserrors.UnaryServerInterceptor()),
+	)
+	caeruslinks.RegisterLinksServiceServer(server.grpcServer, server)
+	healthpb.RegisterHealthServer(server.grpcServer, health.NewServer())
+
+	return server
+}
+
+// Serve accepts the incoming connections on the given listener, blocking until the server is stopped
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpcServer.Serve(listener)
+}
+
+// ServeInMemory starts serving the calls using an in-memory listener, and returns a client connection to it
+// built using the given options
+func (s *Server) ServeInMemory(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	listener := bufconn.Listen(bufconnSize)
+	go func() {
+		_ = s.Serve(listener)
+	}()
+
+	return grpc.Dial(
+		"bufnet",
+		append([]grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		}, opts...)...,
+	)
+}
+
+// Stop stops the server, closing all the open connections
+func (s *Server) Stop() {
+	s.grpcServer.Stop()
+}
+
+// authenticate makes sure the incoming calls are authorized using the API key of the server
+func (s *Server) authenticate(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := md.Get("authorization")
+	if len(authorization) == 0 || authorization[0] != fmt.Sprintf("Bearer %s", s.apiKey) {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+
+	return handler(ctx, req)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// generateLink validates the given request and generates the associated link
+func (s *Server) generateLink(req caeruslinks.GenerateDeepLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.handler.HandleGenerateDeepLinkRequest(req)
+}
+
+// CreateAddressLink implements caeruslinks.LinksServiceServer
+func (s *Server) CreateAddressLink(_ context.Context, request *caeruslinks.CreateAddressLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	return s.generateLink(caeruslinks.NewGenerateAddressLinkRequest(appID, request.Address, request.Chain))
+}
+
+// CreateViewProfileLink implements caeruslinks.LinksServiceServer
+func (s *Server) CreateViewProfileLink(_ context.Context, request *caeruslinks.CreateViewProfileLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	return s.generateLink(caeruslinks.NewGenerateViewProfileLinkRequest(appID, request.Address, request.Chain))
+}
+
+// CreateSendLink implements caeruslinks.LinksServiceServer
+func (s *Server) CreateSendLink(_ context.Context, request *caeruslinks.CreateSendLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	return s.generateLink(caeruslinks.NewGenerateSendTokensLinkRequest(appID, request.Address, request.Chain, request.Amount))
+}
+
+// CreateLink implements caeruslinks.LinksServiceServer
+func (s *Server) CreateLink(_ context.Context, request *caeruslinks.CreateLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	req := caeruslinks.NewGenerateGenericDeepLinkRequest(appID, request.LinkConfiguration, request.ApiKey)
+	err := req.Validate()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.handler.HandleGenerateGenericDeepLinkRequest(req)
+}
+
+// GetLinkConfig implements caeruslinks.LinksServiceServer
+func (s *Server) GetLinkConfig(_ context.Context, request *caeruslinks.GetLinkConfigRequest) (*caerustypes.LinkConfig, error) {
+	return s.handler.HandleGetLinkConfigRequest(request)
+}
@@ -0,0 +1,76 @@
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	caeruslinks "github.com/desmos-labs/caerus/routes/links"
+	caerustypes "github.com/desmos-labs/caerus/types"
+)
+
+var (
+	_ caeruslinks.Database        = &store{}
+	_ caeruslinks.DeepLinksClient = &store{}
+)
+
+// store keeps the configurations of the created links in memory, and generates the links URLs.
+// Each URL is made of the base URL followed by a random identifier, so that it never collides with the URL of a link
+// created before a restart and still stored by the APIs. The configurations, instead, are lost upon restart
+type store struct {
+	baseURL string
+
+	mu      sync.RWMutex
+	configs map[string]*caerustypes.LinkConfig
+}
+
+// newStore returns a new store instance that generates the links URLs using the given base URL
+func newStore(baseURL string) *store {
+	return &store{
+		baseURL: baseURL,
+		configs: make(map[string]*caerustypes.LinkConfig),
+	}
+}
+
+// CreateDynamicLink implements caeruslinks.DeepLinksClient
+func (s *store) CreateDynamicLink(_ string, _ *caerustypes.LinkConfig) (string, error) {
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", fmt.Errorf("error while generating link identifier: %w", err)
+	}
+
+	return fmt.Sprintf("%s/%s", s.baseURL, hex.EncodeToString(id)), nil
+}
+
+// GetAppDeepLinksRateLimit implements caeruslinks.Database
+func (s *store) GetAppDeepLinksRateLimit(_ string) (uint64, error) {
+	// No rate limit is applied
+	return 0, nil
+}
+
+// GetAppDeepLinksCount implements caeruslinks.Database
+func (s *store) GetAppDeepLinksCount(_ string) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return uint64(len(s.configs)), nil
+}
+
+// SaveCreatedDeepLink implements caeruslinks.Database
+func (s *store) SaveCreatedDeepLink(link *caerustypes.CreatedDeepLink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.configs[link.URL] = link.Config
+	return nil
+}
+
+// GetDeepLinkConfig implements caeruslinks.Database
+func (s *store) GetDeepLinkConfig(link string) (*caerustypes.LinkConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.configs[link], nil
+}
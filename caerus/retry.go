@@ -0,0 +1,68 @@
+package caerus
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryConfig contains the configuration used to retry the idempotent calls performed to Caerus
+type RetryConfig struct {
+	// MaxAttempts represents the maximum number of times a call is performed, including the first one
+	MaxAttempts int `yaml:"max_attempts"`
+
+	// InitialBackoff represents the maximum time waited before the first retry.
+	// The backoff is doubled after each retry, and the actual waiting time is randomized to avoid retry storms
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+
+	// MaxBackoff represents the maximum time waited between two attempts
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// DefaultRetryConfig returns the default RetryConfig instance
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// withRetry performs the given call, retrying it using a jittered exponential backoff if it fails with a
+// transient error. It stops retrying as soon as the given context is done.
+// It must be used only for idempotent calls
+func withRetry(ctx context.Context, cfg *RetryConfig, call func() error) error {
+	backoff := cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= cfg.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		// Wait for a random time between half the backoff and the whole backoff
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)) //nolint:gosec // No need for crypto randomness
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+
+		backoff *= 2
+		if backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+}
+
+// isRetryable tells whether a call that failed with the given error can be retried
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,103 @@
+package caerus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryTestSuite(t *testing.T) {
+	suite.Run(t, new(RetryTestSuite))
+}
+
+// RetryTestSuite tests the retries of the idempotent calls
+type RetryTestSuite struct {
+	suite.Suite
+
+	cfg *RetryConfig
+}
+
+func (suite *RetryTestSuite) SetupTest() {
+	suite.cfg = &RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+}
+
+// callFailingWith returns a call that always fails with the given error, along with the counter of its attempts
+func callFailingWith(err error) (func() error, *int) {
+	attempts := 0
+	return func() error {
+		attempts++
+		return err
+	}, &attempts
+}
+
+func (suite *RetryTestSuite) TestRetriesTransientErrors() {
+	for _, code := range []codes.Code{codes.Unavailable, codes.DeadlineExceeded} {
+		call, attempts := callFailingWith(status.Error(code, "transient"))
+		err := withRetry(context.Background(), suite.cfg, call)
+		suite.Require().Equal(code, status.Code(err))
+		suite.Require().Equal(3, *attempts, "wrong attempts for %s", code)
+	}
+}
+
+func (suite *RetryTestSuite) TestStopsOnSuccess() {
+	attempts := 0
+	err := withRetry(context.Background(), suite.cfg, func() error {
+		attempts++
+		if attempts == 1 {
+			return errUnavailable
+		}
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(2, attempts)
+}
+
+func (suite *RetryTestSuite) TestDoesNotRetryOtherErrors() {
+	testErrors := []error{
+		status.Error(codes.InvalidArgument, "invalid"),
+		status.Error(codes.NotFound, "not found"),
+		status.Error(codes.Internal, "internal"),
+		ErrCircuitOpen,
+		errors.New("generic error"),
+	}
+
+	for _, testErr := range testErrors {
+		call, attempts := callFailingWith(testErr)
+		err := withRetry(context.Background(), suite.cfg, call)
+		suite.Require().Equal(testErr, err)
+		suite.Require().Equal(1, *attempts, "wrong attempts for %s", testErr)
+	}
+}
+
+func (suite *RetryTestSuite) TestStopsWhenContextIsDone() {
+	suite.cfg.InitialBackoff = time.Hour
+	suite.cfg.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	call, attempts := callFailingWith(errUnavailable)
+	err := withRetry(ctx, suite.cfg, call)
+	suite.Require().Equal(errUnavailable, err)
+	suite.Require().Equal(1, *attempts)
+}
+
+func (suite *RetryTestSuite) TestBreakerOpenDuringRetries() {
+	breaker := NewCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Hour})
+
+	call, attempts := callFailingWith(errUnavailable)
+	err := withRetry(context.Background(), suite.cfg, func() error {
+		return breaker.Execute(context.Background(), call)
+	})
+	suite.Require().ErrorIs(err, ErrCircuitOpen)
+	suite.Require().Equal(1, *attempts)
+}
@@ -0,0 +1,278 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	caeruslinks "github.com/desmos-labs/caerus/routes/links"
+
+	"github.com/desmos-labs/dpm-apis/routes/auth"
+	"github.com/desmos-labs/dpm-apis/routes/links"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	// maxResponseSize represents the maximum size of the response bodies read by the client
+	maxResponseSize = 10 << 20
+)
+
+// Client allows to perform requests to the APIs, using the same request and response types of the server.
+// All the links are returned as JSON, since the QR codes are meant to be requested directly by the browsers
+type Client struct {
+	cfg        *Config
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a new Client instance that performs the requests using the given HTTP client.
+// If the given HTTP client is nil, http.DefaultClient is used instead
+func NewClient(cfg *Config, httpClient *http.Client) (*Client, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		cfg:        cfg,
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		httpClient: httpClient,
+	}, nil
+}
+
+// WithToken returns a copy of this client that authenticates the requests using the given token.
+// It can be used to perform the requests on behalf of a user after getting their session token using Login
+func (c *Client) WithToken(token string) *Client {
+	cfg := *c.cfg
+	cfg.Token = token
+	return &Client{
+		cfg:        &cfg,
+		baseURL:    c.baseURL,
+		httpClient: c.httpClient,
+	}
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// CreateAddressLink creates a deep link that allows the user to select what action to take on the given address
+func (c *Client) CreateAddressLink(ctx context.Context, req *links.CreateAddressLinkRequest) (*links.CreateLinkResponse, error) {
+	path := fmt.Sprintf("/deep-links/%s", url.PathEscape(req.Address))
+	return c.createLink(ctx, path, linkQuery(req.ChainType, req.CreationOptions))
+}
+
+// CreateViewProfileLink creates a deep link that shows the profile of the given address
+func (c *Client) CreateViewProfileLink(ctx context.Context, req *links.CreateViewProfileLinkRequest) (*links.CreateLinkResponse, error) {
+	path := fmt.Sprintf("/deep-links/%s/view-profile", url.PathEscape(req.Address))
+	return c.createLink(ctx, path, linkQuery(req.ChainType, req.CreationOptions))
+}
+
+// CreateSendLink creates a deep link that allows the user to send the given amount of tokens to the given address
+func (c *Client) CreateSendLink(ctx context.Context, req *links.CreateSendLinkRequest) (*links.CreateLinkResponse, error) {
+	query := linkQuery(req.ChainType, req.CreationOptions)
+	if !req.Amount.Empty() {
+		query.Set(links.AmountKey, req.Amount.String())
+	}
+
+	path := fmt.Sprintf("/deep-links/%s/send", url.PathEscape(req.Address))
+	return c.createLink(ctx, path, query)
+}
+
+// createLink creates a deep link by performing a GET request to the given path with the given query params
+func (c *Client) createLink(ctx context.Context, path string, query url.Values) (*links.CreateLinkResponse, error) {
+	var res links.CreateLinkResponse
+	idempotent := query.Get(links.ForceNewKey) == ""
+	err := c.do(ctx, http.MethodGet, path, query, nil, idempotent, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateLink creates a deep link having the given custom configuration
+func (c *Client) CreateLink(ctx context.Context, req *links.CreateLinkRequest) (*links.CreateLinkResponse, error) {
+	query := url.Values{}
+	if req.ForceNew {
+		query.Set(links.ForceNewKey, "true")
+	}
+
+	var res links.CreateLinkResponse
+	err := c.do(ctx, http.MethodPost, "/deep-links", query, req, !req.ForceNew, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateLinksBatch creates multiple deep links at once.
+// The failure of a single link is reported inside its result, and does not make the whole request fail
+func (c *Client) CreateLinksBatch(ctx context.Context, req *links.CreateLinksBatchRequest) (*links.CreateLinksBatchResponse, error) {
+	idempotent := true
+	for _, linkReq := range req.Requests {
+		if linkReq.ForceNew {
+			idempotent = false
+		}
+	}
+
+	var res links.CreateLinksBatchResponse
+	err := c.do(ctx, http.MethodPost, "/deep-links/batch", nil, req, idempotent, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetLinkConfig returns the configuration of the deep link having the given URL.
+// If the link does not exist, it returns an Error having the utils.ErrCodeLinkNotFound code
+func (c *Client) GetLinkConfig(ctx context.Context, req *links.GetLinkConfigRequest) (*links.GetLinkConfigResponse, error) {
+	query := url.Values{}
+	query.Set("url", req.Url)
+
+	var res links.GetLinkConfigResponse
+	err := c.do(ctx, http.MethodGet, "/deep-links/config", query, nil, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetAddressLinks returns the deep links that have been created for the given address, from the most recent one.
+// If the request does not contain any pagination, the default one of the APIs is used
+func (c *Client) GetAddressLinks(ctx context.Context, req *links.GetAddressLinksRequest) (*links.GetAddressLinksResponse, error) {
+	query := url.Values{}
+	if req.Pagination != nil {
+		query.Set(links.OffsetKey, strconv.FormatUint(req.Pagination.Offset, 10))
+		query.Set(links.LimitKey, strconv.FormatUint(req.Pagination.Limit, 10))
+	}
+
+	var res links.GetAddressLinksResponse
+	path := fmt.Sprintf("/addresses/%s/deep-links", url.PathEscape(req.Address))
+	err := c.do(ctx, http.MethodGet, path, query, nil, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// linkQuery returns the query params used to create a link for the given chain type using the given options
+func linkQuery(chainType caeruslinks.ChainType, options links.CreationOptions) url.Values {
+	query := url.Values{}
+	query.Set(links.ChainTypeKey, strings.ToLower(chainType.String()))
+	if options.ForceNew {
+		query.Set(links.ForceNewKey, "true")
+	}
+	return query
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// GetNonce returns a new nonce that the user identified by the given address has to sign in order to log in
+func (c *Client) GetNonce(ctx context.Context, req *auth.NonceRequest) (*auth.NonceResponse, error) {
+	var res auth.NonceResponse
+	err := c.do(ctx, http.MethodPost, "/auth/nonce", nil, req, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Login exchanges the given signed nonce for a session token, which can be used with WithToken.
+// Since each nonce can be used only once, the request is never retried
+func (c *Client) Login(ctx context.Context, req *auth.LoginRequest) (*auth.LoginResponse, error) {
+	var res auth.LoginResponse
+	err := c.do(ctx, http.MethodPost, "/auth/login", nil, req, false, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// do performs a request having the given method, path, query params and JSON body, decoding the JSON response
+// into the given result. If the request is idempotent, it is retried when it fails with a transient error.
+// The identifier of the request contained inside the given context, if any, is forwarded to the APIs
+func (c *Client) do(
+	ctx context.Context, method string, path string, query url.Values, body interface{}, idempotent bool, result interface{},
+) error {
+	var bodyBz []byte
+	if body != nil {
+		var err error
+		bodyBz, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error while serializing request body: %w", err)
+		}
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	call := func() error {
+		return c.doOnce(ctx, method, endpoint, bodyBz, result)
+	}
+
+	if !idempotent {
+		return call()
+	}
+	return withRetry(ctx, c.cfg.Retry, call)
+}
+
+// doOnce performs a single attempt of the request having the given method, URL and body
+func (c *Client) doOnce(ctx context.Context, method string, endpoint string, body []byte, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bodyReader)
+	if err != nil {
+		return fmt.Errorf("error while building request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+	if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(utils.RequestIDHeader, requestID)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("error while reading response body: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return newErrorFromResponse(res, resBody)
+	}
+
+	err = json.Unmarshal(resBody, result)
+	if err != nil {
+		return fmt.Errorf("error while parsing response body: %w", err)
+	}
+
+	return nil
+}
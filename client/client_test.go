@@ -0,0 +1,217 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	caeruslinks "github.com/desmos-labs/caerus/routes/links"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/client"
+	"github.com/desmos-labs/dpm-apis/database/memory"
+	"github.com/desmos-labs/dpm-apis/internal/testutil"
+	"github.com/desmos-labs/dpm-apis/routes/links"
+	"github.com/desmos-labs/dpm-apis/types"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
+
+// ClientTestSuite tests the client against a real APIs server, which calls a fake Caerus server
+type ClientTestSuite struct {
+	suite.Suite
+
+	address string
+
+	fakeCaerus *testutil.FakeCaerus
+	apiServer  *httptest.Server
+	client     *client.Client
+}
+
+func (suite *ClientTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	suite.address = testutil.TestAddress()
+}
+
+func (suite *ClientTestSuite) SetupTest() {
+	fakeCaerus, err := testutil.StartFakeCaerus()
+	suite.Require().NoError(err)
+	suite.fakeCaerus = fakeCaerus
+
+	router, err := testutil.NewLinksRouter(fakeCaerus.Client(testutil.CaerusAPIKey), memory.NewDatabase(), testutil.TrackerStub{})
+	suite.Require().NoError(err)
+	suite.apiServer = httptest.NewServer(router)
+
+	suite.client = suite.newClient(suite.apiServer.URL, testutil.APIKey)
+}
+
+func (suite *ClientTestSuite) TearDownTest() {
+	suite.apiServer.Close()
+	suite.Require().NoError(suite.fakeCaerus.Stop())
+}
+
+// newClient returns a new client that sends the requests to the given URL using the given token
+func (suite *ClientTestSuite) newClient(url string, token string) *client.Client {
+	cfg := client.DefaultConfig(url)
+	cfg.Token = token
+	cfg.Retry.InitialBackoff = time.Millisecond
+	cfg.Retry.MaxBackoff = 10 * time.Millisecond
+
+	c, err := client.NewClient(cfg, nil)
+	suite.Require().NoError(err)
+	return c
+}
+
+// getCustomData returns the custom data of the link having the given URL, making sure the link exists
+func (suite *ClientTestSuite) getCustomData(deepLink string) map[string]interface{} {
+	res, err := suite.client.GetLinkConfig(context.Background(), links.NewGetLinkConfigRequest(deepLink, ""))
+	suite.Require().NoError(err)
+	suite.Require().Equal(deepLink, res.DeepLink)
+
+	var customData map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(res.Config.CustomData, &customData))
+	return customData
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+func (suite *ClientTestSuite) TestInvalidConfig() {
+	_, err := client.NewClient(client.DefaultConfig("dpm-apis.desmos.network"), nil)
+	suite.Require().Error(err)
+}
+
+func (suite *ClientTestSuite) TestCreateAddressLink() {
+	req := links.NewCreateAddressLinkRequest(suite.address, caeruslinks.ChainType_TESTNET)
+	res, err := suite.client.CreateAddressLink(context.Background(), req)
+	suite.Require().NoError(err)
+	suite.Require().True(strings.HasPrefix(res.DeepLink, testutil.BaseURL+"/"))
+	deepLink := res.DeepLink
+
+	customData := suite.getCustomData(res.DeepLink)
+	suite.Require().Equal(suite.address, customData["address"])
+	suite.Require().Equal("testnet", customData["chain_type"])
+
+	// Equal requests should return the same link, unless a new one is requested
+	res, err = suite.client.CreateAddressLink(context.Background(), req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(deepLink, res.DeepLink)
+
+	req.CreationOptions = links.NewCreationOptions(true, "")
+	res, err = suite.client.CreateAddressLink(context.Background(), req)
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(deepLink, res.DeepLink)
+}
+
+func (suite *ClientTestSuite) TestCreateViewProfileLink() {
+	req := links.NewCreateViewProfileLinkRequest(suite.address, caeruslinks.ChainType_MAINNET)
+	res, err := suite.client.CreateViewProfileLink(context.Background(), req)
+	suite.Require().NoError(err)
+
+	customData := suite.getCustomData(res.DeepLink)
+	suite.Require().Equal("view_profile", customData["action"])
+	suite.Require().Equal("mainnet", customData["chain_type"])
+}
+
+func (suite *ClientTestSuite) TestCreateSendLink() {
+	amount := sdk.NewCoins(sdk.NewInt64Coin("udsm", 10))
+	req := links.NewCreateSendLinkRequest(suite.address, amount, caeruslinks.ChainType_MAINNET)
+	res, err := suite.client.CreateSendLink(context.Background(), req)
+	suite.Require().NoError(err)
+
+	customData := suite.getCustomData(res.DeepLink)
+	suite.Require().Equal("send_tokens", customData["action"])
+	suite.Require().Equal("10udsm", customData["amount"])
+}
+
+func (suite *ClientTestSuite) TestCreateLink() {
+	res, err := suite.client.CreateLink(context.Background(), &links.CreateLinkRequest{
+		Data: map[string]interface{}{"key": "value"},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal("value", suite.getCustomData(res.DeepLink)["key"])
+}
+
+func (suite *ClientTestSuite) TestCreateLinksBatch() {
+	res, err := suite.client.CreateLinksBatch(context.Background(), &links.CreateLinksBatchRequest{
+		Requests: []*links.BatchLinkRequest{
+			{Type: links.LinkTypeAddress, Address: suite.address, ChainType: "mainnet"},
+			{Type: links.LinkTypeAddress, Address: "invalid", ChainType: "mainnet"},
+		},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(res.Results, 2)
+	suite.Require().NotEmpty(res.Results[0].DeepLink)
+	suite.Require().Equal(utils.ErrCodeInvalidAddress, res.Results[1].Code)
+}
+
+func (suite *ClientTestSuite) TestGetAddressLinks() {
+	req := links.NewCreateAddressLinkRequest(suite.address, caeruslinks.ChainType_MAINNET)
+	linkRes, err := suite.client.CreateAddressLink(context.Background(), req)
+	suite.Require().NoError(err)
+
+	res, err := suite.client.GetAddressLinks(context.Background(), links.NewGetAddressLinksRequest(suite.address, types.NewPagination(0, 10)))
+	suite.Require().NoError(err)
+	suite.Require().Len(res.Links, 1)
+	suite.Require().Equal(linkRes.DeepLink, res.Links[0].URL)
+	suite.Require().Equal(uint64(10), res.Pagination.Limit)
+}
+
+func (suite *ClientTestSuite) TestErrors() {
+	ctx := utils.ContextWithRequestID(context.Background(), "test-request-id")
+	_, err := suite.client.GetLinkConfig(ctx, links.NewGetLinkConfigRequest(testutil.BaseURL+"/not-found", ""))
+	suite.Require().True(client.IsErrorCode(err, utils.ErrCodeLinkNotFound))
+
+	var apiErr *client.Error
+	suite.Require().ErrorAs(err, &apiErr)
+	suite.Require().Equal(http.StatusNotFound, apiErr.StatusCode)
+	suite.Require().Equal("test-request-id", apiErr.RequestID)
+
+	_, err = suite.client.CreateAddressLink(context.Background(), links.NewCreateAddressLinkRequest("invalid", caeruslinks.ChainType_MAINNET))
+	suite.Require().ErrorAs(err, &apiErr)
+	suite.Require().Equal(utils.ErrCodeInvalidAddress, apiErr.Code)
+	suite.Require().Equal(links.AddressKey, apiErr.Param)
+
+	unauthenticated := suite.client.WithToken("")
+	_, err = unauthenticated.CreateAddressLink(context.Background(), links.NewCreateAddressLinkRequest(suite.address, caeruslinks.ChainType_MAINNET))
+	suite.Require().True(client.IsErrorCode(err, utils.ErrCodeUnauthorized))
+}
+
+func (suite *ClientTestSuite) TestRetries() {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.Header().Set("Content-Type", utils.ProblemContentType)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":503,"code":"UPSTREAM_UNAVAILABLE","detail":"caerus unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"deep_link":"` + testutil.BaseURL + `/1"}`))
+	}))
+	defer server.Close()
+
+	c := suite.newClient(server.URL, testutil.APIKey)
+
+	// Idempotent requests should be retried
+	req := links.NewCreateAddressLinkRequest(suite.address, caeruslinks.ChainType_MAINNET)
+	res, err := c.CreateAddressLink(context.Background(), req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(testutil.BaseURL+"/1", res.DeepLink)
+	suite.Require().Equal(int32(3), attempts.Load())
+
+	// Requests forcing the creation of a new link should not be retried
+	attempts.Store(0)
+	req.CreationOptions = links.NewCreationOptions(true, "")
+	_, err = c.CreateAddressLink(context.Background(), req)
+	suite.Require().True(client.IsErrorCode(err, utils.ErrCodeUpstreamUnavailable))
+	suite.Require().Equal(int32(1), attempts.Load())
+}
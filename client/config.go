@@ -0,0 +1,62 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Config contains the configuration of a Client
+type Config struct {
+	// BaseURL represents the URL at which the APIs are exposed (e.g. https://dpm-apis.desmos.network)
+	BaseURL string
+
+	// Token represents the API key or the wallet session token used to authenticate the requests, if any
+	Token string
+
+	// Timeout represents the maximum duration of each attempt performed to the APIs
+	Timeout time.Duration
+
+	// Retry contains the configuration used to retry the idempotent requests
+	Retry *RetryConfig
+}
+
+// DefaultConfig returns the default Config instance pointing to the given base URL, which does not contain any token
+func DefaultConfig(baseURL string) *Config {
+	return &Config{
+		BaseURL: baseURL,
+		Timeout: 30 * time.Second,
+		Retry:   DefaultRetryConfig(),
+	}
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found
+func (c *Config) Validate() error {
+	var errs []error
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		errs = append(errs, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", c.BaseURL))
+	}
+
+	if c.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("invalid timeout: must be a positive duration"))
+	}
+
+	if c.Retry == nil {
+		errs = append(errs, fmt.Errorf("missing retry"))
+	} else {
+		if c.Retry.MaxAttempts <= 0 {
+			errs = append(errs, fmt.Errorf("invalid retry.max_attempts: must be a positive integer"))
+		}
+		if c.Retry.InitialBackoff <= 0 {
+			errs = append(errs, fmt.Errorf("invalid retry.initial_backoff: must be a positive duration"))
+		}
+		if c.Retry.MaxBackoff < c.Retry.InitialBackoff {
+			errs = append(errs, fmt.Errorf("invalid retry.max_backoff: must not be lower than initial_backoff"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
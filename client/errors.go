@@ -0,0 +1,85 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+// Error represents an error returned by the APIs.
+// Its fields match the ones of the utils.ProblemResponse returned by the APIs
+type Error struct {
+	// StatusCode represents the HTTP status code of the response
+	StatusCode int
+
+	// Code represents the machine-readable code of the error (e.g. utils.ErrCodeInvalidAddress).
+	// It is empty if the response did not follow the APIs error format (e.g. if it was returned by a proxy)
+	Code string
+
+	// Message represents the human-readable description of the error
+	Message string
+
+	// Param represents the name of the request parameter that caused the error, if any
+	Param string
+
+	// RequestID represents the identifier of the request that caused the error, if any
+	RequestID string
+
+	// RetryAfter represents the time that should be waited before retrying the request, if the APIs specified it
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("status %d", e.StatusCode)
+	if e.Code != "" {
+		msg += fmt.Sprintf(" (%s)", e.Code)
+	}
+	msg += ": " + e.Message
+	if e.Param != "" {
+		msg += fmt.Sprintf(" (param: %s)", e.Param)
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request id: %s)", e.RequestID)
+	}
+	return msg
+}
+
+// newErrorFromResponse returns the Error represented by the given response, which must have a non-2xx status code
+func newErrorFromResponse(res *http.Response, body []byte) *Error {
+	apiErr := &Error{
+		StatusCode: res.StatusCode,
+		Message:    utils.StatusText(res.StatusCode),
+		RequestID:  res.Header.Get(utils.RequestIDHeader),
+	}
+
+	var problem utils.ProblemResponse
+	if json.Unmarshal(body, &problem) == nil && problem.Code != "" {
+		apiErr.Code = problem.Code
+		apiErr.Message = problem.Detail
+		apiErr.Param = problem.Param
+		if problem.RequestID != "" {
+			apiErr.RequestID = problem.RequestID
+		}
+	} else if text := strings.TrimSpace(string(body)); text != "" && len(text) <= 256 {
+		apiErr.Message = text
+	}
+
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return apiErr
+}
+
+// IsErrorCode tells whether the given error has been returned by the APIs with the given code
+// (e.g. utils.ErrCodeLinkNotFound)
+func IsErrorCode(err error, code string) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryConfig contains the configuration used to retry the idempotent requests performed to the APIs
+type RetryConfig struct {
+	// MaxAttempts represents the maximum number of times a request is performed, including the first one
+	MaxAttempts int
+
+	// InitialBackoff represents the maximum time waited before the first retry.
+	// The backoff is doubled after each retry, and the actual waiting time is randomized to avoid retry storms
+	InitialBackoff time.Duration
+
+	// MaxBackoff represents the maximum time waited between two attempts.
+	// The time requested by the APIs using the Retry-After header is honored only if it is not greater than this
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryConfig returns the default RetryConfig instance
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// withRetry performs the given call, retrying it using a jittered exponential backoff if it fails with a
+// transient error. It stops retrying as soon as the given context is done.
+// It must be used only for idempotent requests
+func withRetry(ctx context.Context, cfg *RetryConfig, call func() error) error {
+	backoff := cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= cfg.MaxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+
+		// Wait for a random time between half the backoff and the whole backoff, unless the APIs told how long to wait
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)) //nolint:gosec // No need for crypto randomness
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			if apiErr.RetryAfter > cfg.MaxBackoff {
+				return err
+			}
+			wait = apiErr.RetryAfter
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+
+		backoff *= 2
+		if backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+}
+
+// isRetryable tells whether a request that failed with the given error can be retried.
+// The network errors are always retryable, while the errors returned by the APIs are retryable only if they are
+// caused by the rate limits or by a temporary failure of the APIs or of Caerus
+func isRetryable(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
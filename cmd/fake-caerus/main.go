@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	caerusutils "github.com/desmos-labs/caerus/utils"
+	"github.com/rs/zerolog/log"
+
+	"github.com/desmos-labs/dpm-apis/caerus"
+	"github.com/desmos-labs/dpm-apis/caerus/fake"
+)
+
+const (
+	EnvFakeCaerusAddress = "FAKE_CAERUS_ADDRESS"
+	EnvFakeCaerusBaseURL = "FAKE_CAERUS_BASE_URL"
+)
+
+// This command runs a fake Caerus server that can be used to run the APIs locally without a real Caerus instance.
+// The server accepts the calls authorized using the same CAERUS_API_KEY that is used by the APIs
+func main() {
+	apiKey := caerusutils.GetEnvOr(caerus.EnvCaerusAPIKey, "")
+	if apiKey == "" {
+		panic("missing " + caerus.EnvCaerusAPIKey)
+	}
+
+	address := caerusutils.GetEnvOr(EnvFakeCaerusAddress, "localhost:4000")
+	baseURL := caerusutils.GetEnvOr(EnvFakeCaerusBaseURL, fake.DefaultBaseURL)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		panic(err)
+	}
+
+	server := fake.NewServer(apiKey, baseURL)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+		server.Stop()
+	}()
+
+	log.Info().Str("address", address).Msg("Starting fake Caerus server")
+	err = server.Serve(listener)
+	if err != nil {
+		panic(err)
+	}
+}
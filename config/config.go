@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/authentication"
+	"github.com/desmos-labs/dpm-apis/cache"
+	"github.com/desmos-labs/dpm-apis/caerus"
+	"github.com/desmos-labs/dpm-apis/cors"
+	"github.com/desmos-labs/dpm-apis/database"
+	"github.com/desmos-labs/dpm-apis/logging"
+	"github.com/desmos-labs/dpm-apis/ratelimit"
+	"github.com/desmos-labs/dpm-apis/redaction"
+	"github.com/desmos-labs/dpm-apis/routes/links"
+	"github.com/desmos-labs/dpm-apis/tracing"
+)
+
+// Config contains the whole configuration of the APIs.
+// It can be read from a YAML file, and each value can be overridden using the associated env variable
+type Config struct {
+	Server    *ServerConfig     `yaml:"server"`
+	Caerus    *caerus.Config    `yaml:"caerus"`
+	Logging   *logging.Config   `yaml:"logging"`
+	Redaction *redaction.Config `yaml:"redaction"`
+	CORS      *cors.Config      `yaml:"cors"`
+	Analytics *analytics.Config `yaml:"analytics"`
+}
+
+// DefaultConfig returns the default Config instance
+func DefaultConfig() *Config {
+	return &Config{
+		Server:    DefaultServerConfig(),
+		Caerus:    caerus.DefaultConfig(),
+		Logging:   logging.DefaultConfig(),
+		Redaction: redaction.DefaultConfig(),
+		CORS:      cors.DefaultConfig(),
+		Analytics: analytics.DefaultConfig(),
+	}
+}
+
+// Load reads the configuration from the YAML file at the given path, if any, and overrides its values with the
+// ones of the env variables that are set. The values that are not specified anywhere keep their default.
+// If the configuration is not valid, it returns an error containing all the problems found
+func Load(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if path != "" {
+		err := cfg.readFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	errs := applyEnvOverrides(cfg)
+	err := cfg.Validate()
+	if err != nil {
+		errs = append(errs, unwrapJoined(err)...)
+	}
+	errs = append(errs, validateEnvSections()...)
+
+	return cfg, errors.Join(errs...)
+}
+
+// validateEnvSections checks the sections that can only be configured using the env variables, so that their problems
+// are reported along with the ones of the configuration instead of making the server panic at startup.
+// Each problem is prefixed with the name of the section it belongs to
+func validateEnvSections() []error {
+	sections := []struct {
+		name     string
+		validate func() error
+	}{
+		{"authentication", func() error {
+			_, err := authentication.ReadConfigFromEnvVariables()
+			return err
+		}},
+		{"database", func() error {
+			_, err := database.ReadConfigFromEnvVariables()
+			return err
+		}},
+		{"tracing", func() error {
+			_, err := tracing.ReadConfigFromEnvVariables()
+			return err
+		}},
+		{"rate limiting", func() error {
+			_, err := ratelimit.ReadConfigFromEnvVariables()
+			return err
+		}},
+		{"links cache", func() error {
+			_, err := cache.ReadLinkConfigCacheConfigFromEnvVariables()
+			return err
+		}},
+		{"links", func() error {
+			_, err := links.ReadConfigFromEnvVariables()
+			return err
+		}},
+	}
+
+	var errs []error
+	for _, section := range sections {
+		err := section.validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", section.name, err))
+		}
+	}
+	return errs
+}
+
+// readFile reads the values contained inside the YAML file at the given path.
+// Unknown fields are not allowed, so that typos are reported instead of being silently ignored
+func (c *Config) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error while reading the configuration file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	err = decoder.Decode(c)
+	if err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	// Make sure that all the sections are present, even if they have been set to null
+	defaults := DefaultConfig()
+	if c.Server == nil {
+		c.Server = defaults.Server
+	}
+	if c.Caerus == nil {
+		c.Caerus = defaults.Caerus
+	}
+	if c.Logging == nil {
+		c.Logging = defaults.Logging
+	}
+	if c.Redaction == nil {
+		c.Redaction = defaults.Redaction
+	}
+	if c.CORS == nil {
+		c.CORS = defaults.CORS
+	}
+	if c.Analytics == nil {
+		c.Analytics = defaults.Analytics
+	}
+
+	return nil
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found.
+// Each problem is prefixed with the name of the section it belongs to
+func (c *Config) Validate() error {
+	sections := []struct {
+		name     string
+		validate func() error
+	}{
+		{"server", c.Server.Validate},
+		{"caerus", c.Caerus.Validate},
+		{"logging", c.Logging.Validate},
+		{"redaction", c.Redaction.Validate},
+		{"cors", c.CORS.Validate},
+		{"analytics", c.Analytics.Validate},
+	}
+
+	var errs []error
+	for _, section := range sections {
+		err := section.validate()
+		if err == nil {
+			continue
+		}
+
+		for _, sectionErr := range unwrapJoined(err) {
+			errs = append(errs, fmt.Errorf("%s: %w", section.name, sectionErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// unwrapJoined returns the errors that have been joined into the given one, or the error itself if it has not
+// been built using errors.Join
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// FormatError returns a human-readable description of the given configuration error, listing all the problems
+// it contains one per line
+func FormatError(err error) string {
+	var sb strings.Builder
+	sb.WriteString("invalid configuration:")
+	for _, problem := range unwrapJoined(err) {
+		sb.WriteString("\n  - ")
+		sb.WriteString(problem.Error())
+	}
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/authentication"
+	"github.com/desmos-labs/dpm-apis/caerus"
+	"github.com/desmos-labs/dpm-apis/config"
+	"github.com/desmos-labs/dpm-apis/database"
+	"github.com/desmos-labs/dpm-apis/ratelimit"
+	"github.com/desmos-labs/dpm-apis/routes/links"
+	"github.com/desmos-labs/dpm-apis/tracing"
+)
+
+func TestConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+// ConfigTestSuite tests the loading of the configuration
+type ConfigTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ConfigTestSuite) SetupTest() {
+	suite.T().Setenv(caerus.EnvCaerusGRPCAddress, "localhost:4000")
+	suite.T().Setenv(caerus.EnvCaerusAPIKey, "caerus-api-key")
+	suite.T().Setenv(caerus.EnvBranchKey, "branch-key")
+	suite.T().Setenv(authentication.EnvAPIKeys, `[{"id":"test","hash":"`+authentication.HashKey("api-key")+`","scopes":["admin"]}]`)
+}
+
+func (suite *ConfigTestSuite) TestLoad_Valid() {
+	_, err := config.Load("")
+	suite.Require().NoError(err)
+}
+
+func (suite *ConfigTestSuite) TestLoad_InvalidEnvSections() {
+	suite.T().Setenv(authentication.EnvAPIKeys, "")
+	suite.T().Setenv(database.EnvDatabaseType, database.TypePostgres)
+	suite.T().Setenv(tracing.EnvTracingExporter, "invalid")
+	suite.T().Setenv(ratelimit.EnvRateLimitPolicies, `{"create":{"requests":0,"period":"1m","burst":1,"key":"ip"}}`)
+	suite.T().Setenv(links.EnvBatchMaxSize, "-1")
+
+	_, err := config.Load("")
+	suite.Require().Error(err)
+
+	problems := config.FormatError(err)
+	suite.Require().Contains(problems, "authentication: missing API_KEYS_FILE or API_KEYS")
+	suite.Require().Contains(problems, "database: missing DATABASE_URI")
+	suite.Require().Contains(problems, "tracing: invalid TRACING_EXPORTER")
+	suite.Require().Contains(problems, "rate limiting: invalid create rate limiting policy")
+	suite.Require().Contains(problems, "links: invalid LINKS_BATCH_MAX_SIZE")
+}
+
+func (suite *ConfigTestSuite) TestLoad_Analytics() {
+	suite.T().Setenv(analytics.EnvAnalyticsSink, analytics.SinkFile)
+	suite.T().Setenv(analytics.EnvAnalyticsQueueSize, "10")
+
+	_, err := config.Load("")
+	suite.Require().Error(err)
+	suite.Require().Contains(config.FormatError(err), "analytics: missing file_path (ANALYTICS_FILE_PATH)")
+
+	suite.T().Setenv(analytics.EnvAnalyticsFilePath, "events.jsonl")
+	cfg, err := config.Load("")
+	suite.Require().NoError(err)
+	suite.Require().Equal(&analytics.Config{Sink: analytics.SinkFile, FilePath: "events.jsonl", QueueSize: 10}, cfg.Analytics)
+}
+
+func (suite *ConfigTestSuite) TestLoad_TrustedProxies() {
+	cfg, err := config.Load("")
+	suite.Require().NoError(err)
+	suite.Require().Empty(cfg.Server.TrustedProxies)
+
+	suite.T().Setenv(config.EnvServerTrustedProxies, "10.0.0.0/8, 192.168.1.1,proxy")
+	_, err = config.Load("")
+	suite.Require().Error(err)
+	suite.Require().Contains(config.FormatError(err), "server: invalid trusted proxy proxy: must be an IP or a CIDR")
+
+	suite.T().Setenv(config.EnvServerTrustedProxies, "10.0.0.0/8, 192.168.1.1")
+	cfg, err = config.Load("")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"10.0.0.0/8", "192.168.1.1"}, cfg.Server.TrustedProxies)
+}
@@ -0,0 +1,12 @@
+package config
+
+const (
+	EnvConfigFile = "CONFIG_FILE"
+
+	EnvServerDrainDelay      = "SERVER_DRAIN_DELAY"
+	EnvServerReadTimeout     = "SERVER_READ_TIMEOUT"
+	EnvServerWriteTimeout    = "SERVER_WRITE_TIMEOUT"
+	EnvServerRequestTimeout  = "SERVER_REQUEST_TIMEOUT"
+	EnvServerShutdownTimeout = "SERVER_SHUTDOWN_TIMEOUT"
+	EnvServerTrustedProxies  = "SERVER_TRUSTED_PROXIES"
+)
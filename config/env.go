@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	caeruslogging "github.com/desmos-labs/caerus/logging"
+	"github.com/desmos-labs/caerus/runner"
+
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/caerus"
+	"github.com/desmos-labs/dpm-apis/cors"
+	"github.com/desmos-labs/dpm-apis/logging"
+	"github.com/desmos-labs/dpm-apis/redaction"
+)
+
+// envReader allows to override the configuration values using the env variables.
+// Instead of failing on the first invalid value, it collects all the errors so that they can be reported at once
+type envReader struct {
+	errs []error
+}
+
+// lookup returns the value of the env variable having the given name, and whether it is set to a non-empty value
+func (r *envReader) lookup(name string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(name))
+	return value, value != ""
+}
+
+// string overrides the given value with the one of the env variable having the given name, if set
+func (r *envReader) string(name string, value *string) {
+	if envValue, ok := r.lookup(name); ok {
+		*value = envValue
+	}
+}
+
+// strings overrides the given value with the comma-separated values of the env variable having the given name, if set
+func (r *envReader) strings(name string, value *[]string) {
+	envValue, ok := r.lookup(name)
+	if !ok {
+		return
+	}
+
+	var values []string
+	for _, item := range strings.Split(envValue, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	*value = values
+}
+
+// stringMap overrides the given value with the comma-separated key=value pairs of the env variable having the
+// given name (e.g. "debug=plain,trace=plain"), if set
+func (r *envReader) stringMap(name string, value *map[string]string) {
+	envValue, ok := r.lookup(name)
+	if !ok {
+		return
+	}
+
+	values := map[string]string{}
+	for _, item := range strings.Split(envValue, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		key, itemValue, found := strings.Cut(item, "=")
+		if !found {
+			r.errs = append(r.errs, fmt.Errorf("invalid %s: must be a comma-separated list of key=value pairs", name))
+			return
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(itemValue)
+	}
+	*value = values
+}
+
+// int overrides the given value with the integer value of the env variable having the given name, if set
+func (r *envReader) int(name string, value *int) {
+	envValue, ok := r.lookup(name)
+	if !ok {
+		return
+	}
+
+	parsed, err := strconv.Atoi(envValue)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid %s: must be an integer", name))
+		return
+	}
+	*value = parsed
+}
+
+// bool overrides the given value with the boolean value of the env variable having the given name, if set
+func (r *envReader) bool(name string, value *bool) {
+	envValue, ok := r.lookup(name)
+	if !ok {
+		return
+	}
+
+	parsed, err := strconv.ParseBool(envValue)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid %s: must be either true or false", name))
+		return
+	}
+	*value = parsed
+}
+
+// duration overrides the given value with the duration value of the env variable having the given name
+// (e.g. "30s"), if set
+func (r *envReader) duration(name string, value *time.Duration) {
+	envValue, ok := r.lookup(name)
+	if !ok {
+		return
+	}
+
+	parsed, err := time.ParseDuration(envValue)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid %s: must be a duration (e.g. 30s)", name))
+		return
+	}
+	*value = parsed
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// applyEnvOverrides overrides the values of the given configuration with the ones of the env variables that are set,
+// returning the errors of the variables having an invalid value
+func applyEnvOverrides(cfg *Config) []error {
+	reader := &envReader{}
+
+	reader.string(runner.EnvServerAddress, &cfg.Server.Address)
+	reader.int(runner.EnvServerPort, &cfg.Server.Port)
+	reader.duration(EnvServerDrainDelay, &cfg.Server.DrainDelay)
+	reader.duration(EnvServerReadTimeout, &cfg.Server.ReadTimeout)
+	reader.duration(EnvServerWriteTimeout, &cfg.Server.WriteTimeout)
+	reader.duration(EnvServerRequestTimeout, &cfg.Server.RequestTimeout)
+	reader.duration(EnvServerShutdownTimeout, &cfg.Server.ShutdownTimeout)
+	reader.strings(EnvServerTrustedProxies, &cfg.Server.TrustedProxies)
+
+	reader.string(caerus.EnvCaerusMode, &cfg.Caerus.Mode)
+	reader.string(caerus.EnvCaerusGRPCAddress, &cfg.Caerus.GRPCAddress)
+	reader.string(caerus.EnvCaerusAPIKey, &cfg.Caerus.APIKey)
+	reader.string(caerus.EnvBranchKey, &cfg.Caerus.BranchKey)
+	reader.duration(caerus.EnvCaerusRPCTimeout, &cfg.Caerus.RPCTimeout)
+	if cfg.Caerus.Retry != nil {
+		reader.int(caerus.EnvCaerusRetryMaxAttempts, &cfg.Caerus.Retry.MaxAttempts)
+		reader.duration(caerus.EnvCaerusRetryInitialBackoff, &cfg.Caerus.Retry.InitialBackoff)
+		reader.duration(caerus.EnvCaerusRetryMaxBackoff, &cfg.Caerus.Retry.MaxBackoff)
+	}
+	if cfg.Caerus.CircuitBreaker != nil {
+		reader.int(caerus.EnvCaerusBreakerFailureThreshold, &cfg.Caerus.CircuitBreaker.FailureThreshold)
+		reader.duration(caerus.EnvCaerusBreakerOpenDuration, &cfg.Caerus.CircuitBreaker.OpenDuration)
+	}
+
+	reader.string(caeruslogging.EnvLoggingLevel, &cfg.Logging.Level)
+	reader.string(logging.EnvLoggingFormat, &cfg.Logging.Format)
+
+	reader.string(redaction.EnvRedactionKey, &cfg.Redaction.Key)
+	reader.string(redaction.EnvRedactionMode, &cfg.Redaction.Mode)
+	reader.stringMap(redaction.EnvRedactionLevels, &cfg.Redaction.Levels)
+
+	reader.strings(cors.EnvCORSAllowedOrigins, &cfg.CORS.AllowedOrigins)
+	reader.strings(cors.EnvCORSAllowedMethods, &cfg.CORS.AllowedMethods)
+	reader.strings(cors.EnvCORSAllowedHeaders, &cfg.CORS.AllowedHeaders)
+	reader.strings(cors.EnvCORSExposedHeaders, &cfg.CORS.ExposedHeaders)
+	reader.bool(cors.EnvCORSAllowCredentials, &cfg.CORS.AllowCredentials)
+	reader.duration(cors.EnvCORSMaxAge, &cfg.CORS.MaxAge)
+
+	reader.string(analytics.EnvAnalyticsSink, &cfg.Analytics.Sink)
+	reader.string(analytics.EnvAnalyticsFilePath, &cfg.Analytics.FilePath)
+	reader.int(analytics.EnvAnalyticsQueueSize, &cfg.Analytics.QueueSize)
+
+	return reader.errs
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ServerConfig contains the configuration of the HTTP server
+type ServerConfig struct {
+	// Address represents the address where the server listens for connections
+	Address string `yaml:"address"`
+
+	// Port represents the port where the server listens for connections
+	Port int `yaml:"port"`
+
+	// DrainDelay represents how long the server keeps handling requests while reporting itself as not ready
+	// before shutting down
+	DrainDelay time.Duration `yaml:"drain_delay"`
+
+	// ReadTimeout represents the maximum duration for reading each request, including its body
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+
+	// WriteTimeout represents the maximum duration for writing each response
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+
+	// RequestTimeout represents the maximum duration for handling each request, after which the operations
+	// performed to handle it are canceled
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+
+	// ShutdownTimeout represents the maximum duration the server waits for the pending requests to complete
+	// while shutting down. The same duration is then given to each flush of the analytics events and the spans
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// TrustedProxies contains the IPs and CIDRs of the proxies that are allowed to set the client IP using the
+	// X-Forwarded-For and X-Real-IP headers. When empty, the headers are ignored and the client IP is always the
+	// remote address of the connection
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// DefaultServerConfig returns the default ServerConfig instance
+func DefaultServerConfig() *ServerConfig {
+	return &ServerConfig{
+		Address:         "0.0.0.0",
+		Port:            3000,
+		DrainDelay:      5 * time.Second,
+		ReadTimeout:     time.Minute,
+		WriteTimeout:    time.Minute,
+		RequestTimeout:  time.Minute,
+		ShutdownTimeout: 5 * time.Second,
+	}
+}
+
+// ListenAddress returns the address, including the port, where the server listens for connections
+func (c *ServerConfig) ListenAddress() string {
+	return net.JoinHostPort(c.Address, strconv.Itoa(c.Port))
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found
+func (c *ServerConfig) Validate() error {
+	var errs []error
+
+	if c.Port <= 0 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("invalid port %d: must be between 1 and 65535", c.Port))
+	}
+
+	if c.DrainDelay < 0 {
+		errs = append(errs, fmt.Errorf("invalid drain_delay: must not be negative"))
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read_timeout", c.ReadTimeout},
+		{"write_timeout", c.WriteTimeout},
+		{"request_timeout", c.RequestTimeout},
+		{"shutdown_timeout", c.ShutdownTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("invalid %s: must be a positive duration", timeout.name))
+		}
+	}
+
+	for _, proxy := range c.TrustedProxies {
+		if !isIPOrCIDR(proxy) {
+			errs = append(errs, fmt.Errorf("invalid trusted proxy %s: must be an IP or a CIDR", proxy))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isIPOrCIDR tells whether the given value is either a valid IP or a valid CIDR
+func isIPOrCIDR(value string) bool {
+	if net.ParseIP(value) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
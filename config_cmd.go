@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/desmos-labs/dpm-apis/config"
+)
+
+// newConfigCmd returns the command that allows to manage the configuration.
+// The given path is the one of the configuration file specified using the --config flag
+func newConfigCmd(configPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration of the server",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate [file]",
+		Short: "Validate the configuration without starting the server",
+		Long: `Validate the configuration read from the given YAML file, or from the one specified using --config,
+after overriding its values with the ones of the env variables that are set.
+All the problems found are reported at once.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := *configPath
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			_, err := config.Load(path)
+			if err != nil {
+				return errors.New(config.FormatError(err))
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "configuration is valid")
+			return nil
+		},
+	})
+
+	return cmd
+}
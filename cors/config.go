@@ -0,0 +1,150 @@
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	// AllOrigins represents the value that allows all the origins to perform cross-origin requests
+	AllOrigins = "*"
+)
+
+// Config contains the CORS policy of the server
+type Config struct {
+	// AllowedOrigins contains the origins allowed to perform cross-origin requests.
+	// Each origin can be either an exact origin (e.g. "https://app.example.com"), an origin matching all the
+	// subdomains of a domain (e.g. "https://*.example.com"), or AllOrigins to allow all the origins.
+	// If empty, all the cross-origin requests are rejected
+	AllowedOrigins []string `yaml:"allowed_origins"`
+
+	// AllowedMethods contains the methods that can be used while performing cross-origin requests
+	AllowedMethods []string `yaml:"allowed_methods"`
+
+	// AllowedHeaders contains the headers that can be sent while performing cross-origin requests
+	AllowedHeaders []string `yaml:"allowed_headers"`
+
+	// ExposedHeaders contains the response headers that can be read by the clients performing cross-origin requests
+	ExposedHeaders []string `yaml:"exposed_headers"`
+
+	// AllowCredentials tells whether the cross-origin requests can include the user credentials (e.g. cookies)
+	AllowCredentials bool `yaml:"allow_credentials"`
+
+	// MaxAge represents how long the results of a preflight request can be cached by the clients
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// DefaultConfig returns the default Config instance, which does not allow any origin
+func DefaultConfig() *Config {
+	return &Config{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Origin", "Content-Type", "Authorization", utils.RequestIDHeader},
+		ExposedHeaders: []string{
+			utils.RequestIDHeader,
+			"X-RateLimit-Limit",
+			"X-RateLimit-Remaining",
+			"X-RateLimit-Reset",
+			"Retry-After",
+		},
+		MaxAge: 12 * time.Hour,
+	}
+}
+
+// AllowAllOrigins tells whether all the origins are allowed
+func (c *Config) AllowAllOrigins() bool {
+	for _, origin := range c.AllowedOrigins {
+		if origin == AllOrigins {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found
+func (c *Config) Validate() error {
+	var errs []error
+
+	for _, origin := range c.AllowedOrigins {
+		if origin == AllOrigins {
+			continue
+		}
+
+		_, err := parseOrigin(origin)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.AllowCredentials && c.AllowAllOrigins() {
+		errs = append(errs, fmt.Errorf("allow_credentials cannot be enabled when all the origins are allowed"))
+	}
+
+	if len(c.AllowedMethods) == 0 {
+		errs = append(errs, fmt.Errorf("missing allowed_methods"))
+	}
+
+	if c.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("invalid max_age: must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// originMatcher allows to check whether an origin matches an allowed one
+type originMatcher struct {
+	// scheme represents the scheme of the allowed origin, including the "://" separator
+	scheme string
+
+	// host represents the host of the allowed origin, including the port if any.
+	// When matching subdomains, it represents the parent domain prefixed by a dot (e.g. ".example.com")
+	host string
+
+	// subdomains tells whether the matcher allows all the subdomains of the host, instead of the host itself
+	subdomains bool
+}
+
+// parseOrigin parses the given allowed origin, which can be either an exact origin (e.g. "https://app.example.com")
+// or an origin matching all the subdomains of a domain (e.g. "https://*.example.com")
+func parseOrigin(origin string) (*originMatcher, error) {
+	scheme, host, found := strings.Cut(strings.ToLower(origin), "://")
+	if !found || (scheme != "http" && scheme != "https") {
+		return nil, fmt.Errorf("invalid allowed origin %q: must start with http:// or https://", origin)
+	}
+
+	subdomains := strings.HasPrefix(host, "*.")
+	if subdomains {
+		host = strings.TrimPrefix(host, "*")
+	}
+
+	if host == "" || host == "." || strings.ContainsAny(host, "*/@?#") {
+		return nil, fmt.Errorf("invalid allowed origin %q: must be either an origin or an origin having a *. prefix before the domain", origin)
+	}
+
+	return &originMatcher{
+		scheme:     scheme + "://",
+		host:       host,
+		subdomains: subdomains,
+	}, nil
+}
+
+// matches tells whether the given lowercase origin matches the allowed one
+func (m *originMatcher) matches(origin string) bool {
+	host, found := strings.CutPrefix(origin, m.scheme)
+	if !found {
+		return false
+	}
+
+	if !m.subdomains {
+		return host == m.host
+	}
+
+	subdomain, found := strings.CutSuffix(host, m.host)
+	return found && subdomain != "" && !strings.ContainsAny(subdomain, ":/@?#")
+}
@@ -0,0 +1,10 @@
+package cors
+
+const (
+	EnvCORSAllowedOrigins   = "CORS_ALLOWED_ORIGINS"
+	EnvCORSAllowedMethods   = "CORS_ALLOWED_METHODS"
+	EnvCORSAllowedHeaders   = "CORS_ALLOWED_HEADERS"
+	EnvCORSExposedHeaders   = "CORS_EXPOSED_HEADERS"
+	EnvCORSAllowCredentials = "CORS_ALLOW_CREDENTIALS"
+	EnvCORSMaxAge           = "CORS_MAX_AGE"
+)
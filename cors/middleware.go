@@ -0,0 +1,162 @@
+package cors
+
+import (
+	"net/http"
+	"strings"
+
+	gincors "github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// policy represents the CORS policy built from a Config
+type policy struct {
+	allowAll       bool
+	origins        []*originMatcher
+	allowedMethods map[string]bool
+	allowedHeaders map[string]bool
+}
+
+// newPolicy builds a new policy from the given configuration, which must be valid
+func newPolicy(cfg *Config) *policy {
+	p := &policy{
+		allowAll:       cfg.AllowAllOrigins(),
+		allowedMethods: make(map[string]bool, len(cfg.AllowedMethods)),
+		allowedHeaders: make(map[string]bool, len(cfg.AllowedHeaders)),
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == AllOrigins {
+			continue
+		}
+
+		matcher, err := parseOrigin(origin)
+		if err != nil {
+			panic(err)
+		}
+		p.origins = append(p.origins, matcher)
+	}
+
+	for _, method := range cfg.AllowedMethods {
+		p.allowedMethods[strings.ToUpper(method)] = true
+	}
+
+	for _, header := range cfg.AllowedHeaders {
+		p.allowedHeaders[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return p
+}
+
+// isOriginAllowed tells whether the given origin is allowed to perform cross-origin requests
+func (p *policy) isOriginAllowed(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	for _, matcher := range p.origins {
+		if matcher.matches(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPreflight checks whether the preflight request sent from the given origin, asking to use the given method and
+// headers, should be allowed. If not, it returns the reason why it should be rejected
+func (p *policy) checkPreflight(origin string, method string, headers string) (reason string, allowed bool) {
+	if !p.isOriginAllowed(origin) {
+		return "origin not allowed", false
+	}
+
+	if method != "" && !p.allowedMethods[strings.ToUpper(method)] {
+		return "method not allowed", false
+	}
+
+	for _, header := range strings.Split(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !p.allowedHeaders[http.CanonicalHeaderKey(header)] {
+			return "header not allowed: " + header, false
+		}
+	}
+
+	return "", true
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// Middleware returns a Gin handler function that applies the given CORS policy.
+// The cross-origin requests sent from origins that are not allowed are rejected with a 403 Forbidden status, as well
+// as the preflight requests asking to use methods or headers that are not allowed.
+// It panics if the given configuration is not valid
+func Middleware(cfg *Config) gin.HandlerFunc {
+	err := cfg.Validate()
+	if err != nil {
+		panic(err)
+	}
+
+	p := newPolicy(cfg)
+
+	corsConfig := gincors.Config{
+		AllowMethods:     cfg.AllowedMethods,
+		AllowHeaders:     cfg.AllowedHeaders,
+		ExposeHeaders:    cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+	if p.allowAll {
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowOriginFunc = p.isOriginAllowed
+	}
+	corsHandler := gincors.New(corsConfig)
+
+	if len(cfg.AllowedOrigins) == 0 {
+		log.Info().Msg("No CORS origin allowed: all the cross-origin requests will be rejected")
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if !isCrossOrigin(c, origin) {
+			c.Next()
+			return
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			method := c.GetHeader("Access-Control-Request-Method")
+			headers := c.GetHeader("Access-Control-Request-Headers")
+
+			reason, allowed := p.checkPreflight(origin, method, headers)
+			if !allowed {
+				log.Warn().
+					Str("origin", origin).
+					Str("method", method).
+					Str("headers", headers).
+					Str("path", c.Request.URL.Path).
+					Str("reason", reason).
+					Msg("CORS preflight request rejected")
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		} else if !p.isOriginAllowed(origin) {
+			log.Debug().
+				Str("origin", origin).
+				Str("method", c.Request.Method).
+				Str("path", c.Request.URL.Path).
+				Msg("CORS request rejected: origin not allowed")
+		}
+
+		corsHandler(c)
+	}
+}
+
+// isCrossOrigin tells whether the request having the given origin is a cross-origin request
+func isCrossOrigin(c *gin.Context, origin string) bool {
+	if origin == "" {
+		return false
+	}
+
+	host := c.Request.Host
+	return origin != "http://"+host && origin != "https://"+host
+}
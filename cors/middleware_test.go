@@ -0,0 +1,245 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrigin(t *testing.T) {
+	testCases := []struct {
+		name      string
+		origin    string
+		shouldErr bool
+		expected  *originMatcher
+	}{
+		{
+			name:     "exact origin",
+			origin:   "https://app.desmos.network",
+			expected: &originMatcher{scheme: "https://", host: "app.desmos.network"},
+		},
+		{
+			name:     "origin with port",
+			origin:   "http://localhost:3000",
+			expected: &originMatcher{scheme: "http://", host: "localhost:3000"},
+		},
+		{
+			name:     "subdomains origin",
+			origin:   "https://*.desmos.network",
+			expected: &originMatcher{scheme: "https://", host: ".desmos.network", subdomains: true},
+		},
+		{
+			name:     "uppercase origin",
+			origin:   "HTTPS://App.Desmos.Network",
+			expected: &originMatcher{scheme: "https://", host: "app.desmos.network"},
+		},
+		{name: "missing scheme", origin: "app.desmos.network", shouldErr: true},
+		{name: "invalid scheme", origin: "ftp://app.desmos.network", shouldErr: true},
+		{name: "missing host", origin: "https://", shouldErr: true},
+		{name: "only wildcard", origin: "https://*.", shouldErr: true},
+		{name: "wildcard in the middle", origin: "https://app.*.network", shouldErr: true},
+		{name: "wildcard without dot", origin: "https://*desmos.network", shouldErr: true},
+		{name: "path", origin: "https://app.desmos.network/path", shouldErr: true},
+		{name: "user info", origin: "https://user@app.desmos.network", shouldErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			matcher, err := parseOrigin(tc.origin)
+			if tc.shouldErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, matcher)
+		})
+	}
+}
+
+func TestOriginMatcher_Matches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		allowed  string
+		origin   string
+		expected bool
+	}{
+		{name: "exact match", allowed: "https://app.desmos.network", origin: "https://app.desmos.network", expected: true},
+		{name: "different host", allowed: "https://app.desmos.network", origin: "https://web.desmos.network", expected: false},
+		{name: "exact origin does not match subdomains", allowed: "https://desmos.network", origin: "https://app.desmos.network", expected: false},
+		{name: "scheme mismatch", allowed: "https://app.desmos.network", origin: "http://app.desmos.network", expected: false},
+		{name: "matching port", allowed: "http://localhost:3000", origin: "http://localhost:3000", expected: true},
+		{name: "different port", allowed: "http://localhost:3000", origin: "http://localhost:3001", expected: false},
+		{name: "missing port", allowed: "http://localhost:3000", origin: "http://localhost", expected: false},
+		{name: "unexpected port", allowed: "https://app.desmos.network", origin: "https://app.desmos.network:8443", expected: false},
+		{name: "subdomain", allowed: "https://*.desmos.network", origin: "https://app.desmos.network", expected: true},
+		{name: "nested subdomain", allowed: "https://*.desmos.network", origin: "https://a.b.desmos.network", expected: true},
+		{name: "subdomains do not match the apex", allowed: "https://*.desmos.network", origin: "https://desmos.network", expected: false},
+		{name: "suffix attack", allowed: "https://*.desmos.network", origin: "https://evildesmos.network", expected: false},
+		{name: "suffix attack with subdomain", allowed: "https://*.desmos.network", origin: "https://app.evildesmos.network", expected: false},
+		{name: "allowed domain as a subdomain", allowed: "https://*.desmos.network", origin: "https://desmos.network.evil.com", expected: false},
+		{name: "subdomain with port", allowed: "https://*.desmos.network", origin: "https://app.desmos.network:8443", expected: false},
+		{name: "subdomain with user info", allowed: "https://*.desmos.network", origin: "https://evil.com@app.desmos.network", expected: false},
+		{name: "subdomain scheme mismatch", allowed: "https://*.desmos.network", origin: "http://app.desmos.network", expected: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			matcher, err := parseOrigin(tc.allowed)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, matcher.matches(tc.origin))
+		})
+	}
+}
+
+func TestPolicy_IsOriginAllowed(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.AllowedOrigins = []string{"https://app.desmos.network", "https://*.forbole.com"}
+	p := newPolicy(cfg)
+
+	require.True(t, p.isOriginAllowed("https://app.desmos.network"))
+	require.True(t, p.isOriginAllowed("HTTPS://APP.DESMOS.NETWORK"))
+	require.True(t, p.isOriginAllowed("https://Wallet.Forbole.com"))
+	require.False(t, p.isOriginAllowed("https://forbole.com"))
+	require.False(t, p.isOriginAllowed("null"))
+
+	// No origin should be allowed by default
+	require.False(t, newPolicy(DefaultConfig()).isOriginAllowed("https://app.desmos.network"))
+
+	cfg.AllowedOrigins = []string{AllOrigins}
+	require.True(t, newPolicy(cfg).isOriginAllowed("https://any.origin.com"))
+}
+
+func TestPolicy_CheckPreflight(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.AllowedOrigins = []string{"https://app.desmos.network"}
+	p := newPolicy(cfg)
+
+	testCases := []struct {
+		name     string
+		origin   string
+		method   string
+		headers  string
+		expected bool
+	}{
+		{name: "allowed request", origin: "https://app.desmos.network", method: "POST", headers: "Content-Type, Authorization", expected: true},
+		{name: "case insensitive method and headers", origin: "https://app.desmos.network", method: "get", headers: "content-type,x-request-id", expected: true},
+		{name: "no method nor headers", origin: "https://app.desmos.network", expected: true},
+		{name: "origin not allowed", origin: "https://evil.com", method: "GET", expected: false},
+		{name: "method not allowed", origin: "https://app.desmos.network", method: "DELETE", expected: false},
+		{name: "header not allowed", origin: "https://app.desmos.network", method: "GET", headers: "Content-Type, X-Custom", expected: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reason, allowed := p.checkPreflight(tc.origin, tc.method, tc.headers)
+			require.Equal(t, tc.expected, allowed)
+			if tc.expected {
+				require.Empty(t, reason)
+			} else {
+				require.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := DefaultConfig()
+	cfg.AllowedOrigins = []string{"https://*.desmos.network"}
+
+	router := gin.New()
+	router.Use(Middleware(cfg))
+	router.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name           string
+		method         string
+		headers        map[string]string
+		expectedStatus int
+		expectedOrigin string
+	}{
+		{
+			name:           "same origin request",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "http://example.com"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "allowed cross-origin request",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://app.desmos.network"},
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "https://app.desmos.network",
+		},
+		{
+			name:           "cross-origin request from suffix attack origin",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://evildesmos.network"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "allowed preflight",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.desmos.network",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "Content-Type",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedOrigin: "https://app.desmos.network",
+		},
+		{
+			name:   "preflight with disallowed method",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://app.desmos.network",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "preflight with disallowed header",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.desmos.network",
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "preflight from disallowed origin",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://desmos.network",
+				"Access-Control-Request-Method": "GET",
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com/", nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+			require.Equal(t, tc.expectedStatus, res.Code)
+			require.Equal(t, tc.expectedOrigin, res.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
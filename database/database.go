@@ -0,0 +1,112 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/desmos-labs/caerus/utils"
+
+	"github.com/desmos-labs/dpm-apis/database/memory"
+	"github.com/desmos-labs/dpm-apis/database/postgres"
+	"github.com/desmos-labs/dpm-apis/database/sqlite"
+	"github.com/desmos-labs/dpm-apis/types"
+)
+
+const (
+	EnvDatabaseType = "DATABASE_TYPE"
+	EnvDatabaseURI  = "DATABASE_URI"
+
+	TypeMemory   = "memory"
+	TypeSQLite   = "sqlite"
+	TypePostgres = "postgres"
+)
+
+// Database represents the storage used to keep track of the links that have been created
+type Database interface {
+	// GetLinkURLByKey returns the URL of the link that has been created for the request having the given
+	// idempotency key, or an empty string if no link has been created for such request
+	GetLinkURLByKey(key string) (string, error)
+
+	// SaveLinkURL stores the URL of the link that has been created for the request having the given idempotency key
+	SaveLinkURL(key string, url string) error
+
+	// SaveCreatedLink stores the given link
+	SaveCreatedLink(link *types.CreatedLink) error
+
+	// GetAddressLinks returns the links associated with the given address, from the most recent to the oldest one,
+	// along with the total number of links associated with such address
+	GetAddressLinks(address string, pagination *types.Pagination) ([]*types.CreatedLink, uint64, error)
+
+	// Close closes the connection to the database
+	Close() error
+}
+
+// Config contains the configuration of the database
+type Config struct {
+	// Type represents the type of the database (either TypeMemory, TypeSQLite or TypePostgres)
+	Type string
+
+	// URI represents the URI used to connect to the database. It is required by all the types but TypeMemory
+	URI string
+}
+
+// ReadConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// It returns an error if any value is not valid
+func ReadConfigFromEnvVariables() (*Config, error) {
+	cfg := &Config{
+		Type: strings.ToLower(utils.GetEnvOr(EnvDatabaseType, TypeMemory)),
+		URI:  utils.GetEnvOr(EnvDatabaseURI, ""),
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks whether the configuration is valid or not
+func (c *Config) Validate() error {
+	switch c.Type {
+	case TypeMemory:
+		return nil
+
+	case TypeSQLite, TypePostgres:
+		if c.URI == "" {
+			return fmt.Errorf("missing %s", EnvDatabaseURI)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("invalid %s: %s", EnvDatabaseType, c.Type)
+	}
+}
+
+// NewFromEnvVariables returns a new Database instance based on the env variables.
+// It panics if the configuration is not valid or the database cannot be opened
+func NewFromEnvVariables() Database {
+	cfg, err := ReadConfigFromEnvVariables()
+	if err != nil {
+		panic(err)
+	}
+
+	switch cfg.Type {
+	case TypeSQLite:
+		db, err := sqlite.NewDatabase(cfg.URI)
+		if err != nil {
+			panic(err)
+		}
+		return db
+
+	case TypePostgres:
+		db, err := postgres.NewDatabase(cfg.URI)
+		if err != nil {
+			panic(err)
+		}
+		return db
+
+	default:
+		return memory.NewDatabase()
+	}
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/desmos-labs/dpm-apis/types"
+)
+
+// Database represents a database that keeps all the data in memory.
+// It is meant to be used during development or when running a single instance of the APIs
+type Database struct {
+	mu           sync.RWMutex
+	linkURLs     map[string]string
+	createdLinks []*types.CreatedLink
+}
+
+// NewDatabase returns a new Database instance
+func NewDatabase() *Database {
+	return &Database{
+		linkURLs: make(map[string]string),
+	}
+}
+
+// GetLinkURLByKey returns the URL of the link associated with the given idempotency key, if any
+func (db *Database) GetLinkURLByKey(key string) (string, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.linkURLs[key], nil
+}
+
+// SaveLinkURL associates the given link URL with the given idempotency key
+func (db *Database) SaveLinkURL(key string, url string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.linkURLs[key] = url
+	return nil
+}
+
+// Close implements database.Database
+func (db *Database) Close() error {
+	return nil
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// SaveCreatedLink stores the given link
+func (db *Database) SaveCreatedLink(link *types.CreatedLink) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.createdLinks = append(db.createdLinks, link)
+	return nil
+}
+
+// GetAddressLinks returns the links associated with the given address, from the most recent to the oldest one,
+// along with the total number of links associated with such address
+func (db *Database) GetAddressLinks(address string, pagination *types.Pagination) ([]*types.CreatedLink, uint64, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var links []*types.CreatedLink
+	for _, link := range db.createdLinks {
+		if link.Address == address {
+			links = append(links, link)
+		}
+	}
+
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].CreationTime.After(links[j].CreationTime)
+	})
+
+	total := uint64(len(links))
+	if pagination.Offset >= total {
+		return nil, total, nil
+	}
+
+	end := pagination.Offset + pagination.Limit
+	if end > total {
+		end = total
+	}
+
+	return links[pagination.Offset:end], total, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	// Register the PostgreSQL driver
+	_ "github.com/lib/pq"
+
+	dbutils "github.com/desmos-labs/dpm-apis/database/utils"
+	"github.com/desmos-labs/dpm-apis/types"
+)
+
+//go:embed schema/*.sql
+var schemaFS embed.FS
+
+// Database represents a database that stores the data inside a PostgreSQL instance
+type Database struct {
+	SQL *sqlx.DB
+}
+
+// NewDatabase returns a new Database instance connected to the PostgreSQL instance having the given URI.
+// The database schema is created if it does not exist yet
+func NewDatabase(uri string) (*Database, error) {
+	sqlDB, err := sqlx.Open("postgres", uri)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dbutils.CreateSchema(sqlDB, schemaFS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Database{SQL: sqlDB}, nil
+}
+
+// Close closes the connection to the database
+func (db *Database) Close() error {
+	return db.SQL.Close()
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// GetLinkURLByKey returns the URL of the link associated with the given idempotency key, if any
+func (db *Database) GetLinkURLByKey(key string) (string, error) {
+	stmt := `SELECT link_url FROM idempotent_links WHERE idempotency_key = $1`
+
+	var linkURL string
+	err := db.SQL.Get(&linkURL, stmt, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return linkURL, nil
+}
+
+// SaveLinkURL associates the given link URL with the given idempotency key
+func (db *Database) SaveLinkURL(key string, url string) error {
+	stmt := `
+INSERT INTO idempotent_links (idempotency_key, link_url)
+VALUES ($1, $2)
+ON CONFLICT (idempotency_key) DO UPDATE SET link_url = excluded.link_url, creation_time = NOW()`
+
+	_, err := db.SQL.Exec(stmt, key, url)
+	return err
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// SaveCreatedLink stores the given link
+func (db *Database) SaveCreatedLink(link *types.CreatedLink) error {
+	stmt := `
+INSERT INTO created_links (link_url, action, address, amount, chain_type, creator_key, creation_time)
+VALUES (:link_url, :action, :address, :amount, :chain_type, :creator_key, :creation_time)`
+
+	_, err := db.SQL.NamedExec(stmt, link)
+	return err
+}
+
+// GetAddressLinks returns the links associated with the given address, from the most recent to the oldest one,
+// along with the total number of links associated with such address
+func (db *Database) GetAddressLinks(address string, pagination *types.Pagination) ([]*types.CreatedLink, uint64, error) {
+	// Read the total and the page within the same repeatable read transaction, so that they are consistent with
+	// each other even if new links are stored in the meantime
+	tx, err := db.SQL.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck // The transaction only reads data, so it is never committed
+
+	var total uint64
+	err = tx.Get(&total, `SELECT COUNT(id) FROM created_links WHERE address = $1`, address)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stmt := `
+SELECT link_url, action, address, amount, chain_type, creator_key, creation_time
+FROM created_links
+WHERE address = $1
+ORDER BY creation_time DESC, id DESC
+LIMIT $2 OFFSET $3`
+
+	var links []*types.CreatedLink
+	err = tx.Select(&links, stmt, address, pagination.Limit, pagination.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return links, total, nil
+}
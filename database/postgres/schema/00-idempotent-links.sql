@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS idempotent_links
+(
+    idempotency_key TEXT                     NOT NULL PRIMARY KEY,
+    link_url        TEXT                     NOT NULL,
+    creation_time   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
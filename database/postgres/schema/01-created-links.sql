@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS created_links
+(
+    id            BIGSERIAL                NOT NULL PRIMARY KEY,
+    link_url      TEXT                     NOT NULL,
+    action        TEXT                     NOT NULL,
+    address       TEXT                     NOT NULL DEFAULT '',
+    amount        TEXT                     NOT NULL DEFAULT '',
+    chain_type    TEXT                     NOT NULL DEFAULT '',
+    creator_key   TEXT                     NOT NULL DEFAULT '',
+    creation_time TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS created_links_address_index ON created_links (address, creation_time DESC);
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	// Register the SQLite driver
+	_ "github.com/mattn/go-sqlite3"
+
+	dbutils "github.com/desmos-labs/dpm-apis/database/utils"
+	"github.com/desmos-labs/dpm-apis/types"
+)
+
+//go:embed schema/*.sql
+var schemaFS embed.FS
+
+// Database represents a database that stores the data inside an embedded SQLite database
+type Database struct {
+	SQL *sqlx.DB
+}
+
+// NewDatabase returns a new Database instance storing the data inside the SQLite database at the given path.
+// The database schema is created if it does not exist yet
+func NewDatabase(path string) (*Database, error) {
+	sqlDB, err := sqlx.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path))
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite supports a single writer at a time
+	sqlDB.SetMaxOpenConns(1)
+
+	err = dbutils.CreateSchema(sqlDB, schemaFS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Database{SQL: sqlDB}, nil
+}
+
+// Close closes the connection to the database
+func (db *Database) Close() error {
+	return db.SQL.Close()
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// GetLinkURLByKey returns the URL of the link associated with the given idempotency key, if any
+func (db *Database) GetLinkURLByKey(key string) (string, error) {
+	stmt := `SELECT link_url FROM idempotent_links WHERE idempotency_key = ?`
+
+	var linkURL string
+	err := db.SQL.Get(&linkURL, stmt, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return linkURL, nil
+}
+
+// SaveLinkURL associates the given link URL with the given idempotency key
+func (db *Database) SaveLinkURL(key string, url string) error {
+	stmt := `
+INSERT INTO idempotent_links (idempotency_key, link_url)
+VALUES (?, ?)
+ON CONFLICT (idempotency_key) DO UPDATE SET link_url = excluded.link_url, creation_time = CURRENT_TIMESTAMP`
+
+	_, err := db.SQL.Exec(stmt, key, url)
+	return err
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// SaveCreatedLink stores the given link
+func (db *Database) SaveCreatedLink(link *types.CreatedLink) error {
+	stmt := `
+INSERT INTO created_links (link_url, action, address, amount, chain_type, creator_key, creation_time)
+VALUES (:link_url, :action, :address, :amount, :chain_type, :creator_key, :creation_time)`
+
+	_, err := db.SQL.NamedExec(stmt, link)
+	return err
+}
+
+// GetAddressLinks returns the links associated with the given address, from the most recent to the oldest one,
+// along with the total number of links associated with such address
+func (db *Database) GetAddressLinks(address string, pagination *types.Pagination) ([]*types.CreatedLink, uint64, error) {
+	// Read the total and the page within the same transaction, so that they are consistent with each other even
+	// if new links are stored in the meantime
+	tx, err := db.SQL.Beginx()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck // The transaction only reads data, so it is never committed
+
+	var total uint64
+	err = tx.Get(&total, `SELECT COUNT(id) FROM created_links WHERE address = ?`, address)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stmt := `
+SELECT link_url, action, address, amount, chain_type, creator_key, creation_time
+FROM created_links
+WHERE address = ?
+ORDER BY creation_time DESC, id DESC
+LIMIT ? OFFSET ?`
+
+	var links []*types.CreatedLink
+	err = tx.Select(&links, stmt, address, pagination.Limit, pagination.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return links, total, nil
+}
@@ -0,0 +1,93 @@
+package sqlite_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/database/sqlite"
+	"github.com/desmos-labs/dpm-apis/types"
+)
+
+const (
+	testAddress  = "desmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+	otherAddress = "desmos1xqc8xq4jwgd6sj5a9dplm7ycrezqyhwrk3ntqc"
+)
+
+func TestDatabaseTestSuite(t *testing.T) {
+	suite.Run(t, new(DatabaseTestSuite))
+}
+
+// DatabaseTestSuite tests the storage of the links inside an SQLite database
+type DatabaseTestSuite struct {
+	suite.Suite
+
+	db *sqlite.Database
+}
+
+func (suite *DatabaseTestSuite) SetupTest() {
+	db, err := sqlite.NewDatabase(filepath.Join(suite.T().TempDir(), "dpm.db"))
+	suite.Require().NoError(err)
+	suite.db = db
+}
+
+func (suite *DatabaseTestSuite) TearDownTest() {
+	suite.Require().NoError(suite.db.Close())
+}
+
+func (suite *DatabaseTestSuite) TestLinkURLByKey() {
+	linkURL, err := suite.db.GetLinkURLByKey("key")
+	suite.Require().NoError(err)
+	suite.Require().Empty(linkURL)
+
+	suite.Require().NoError(suite.db.SaveLinkURL("key", "https://dpm.link/1"))
+	linkURL, err = suite.db.GetLinkURLByKey("key")
+	suite.Require().NoError(err)
+	suite.Require().Equal("https://dpm.link/1", linkURL)
+
+	// Saving a new link for the same key should replace the previous one
+	suite.Require().NoError(suite.db.SaveLinkURL("key", "https://dpm.link/2"))
+	linkURL, err = suite.db.GetLinkURLByKey("key")
+	suite.Require().NoError(err)
+	suite.Require().Equal("https://dpm.link/2", linkURL)
+}
+
+func (suite *DatabaseTestSuite) TestGetAddressLinks() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	saved := make([]*types.CreatedLink, 5)
+	for i := range saved {
+		saved[i] = types.NewCreatedLink(
+			fmt.Sprintf("https://dpm.link/%d", i), "send", testAddress, "10udsm", "mainnet", "key", start.Add(time.Duration(i)*time.Minute),
+		)
+		suite.Require().NoError(suite.db.SaveCreatedLink(saved[i]))
+	}
+	suite.Require().NoError(suite.db.SaveCreatedLink(
+		types.NewCreatedLink("https://dpm.link/other", "address", otherAddress, "", "testnet", "", start),
+	))
+
+	// The links should be returned from the most recent to the oldest one
+	links, total, err := suite.db.GetAddressLinks(testAddress, types.NewPagination(0, 2))
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(5), total)
+	suite.Require().Len(links, 2)
+	suite.Require().Equal(saved[4].URL, links[0].URL)
+	suite.Require().Equal(saved[3].URL, links[1].URL)
+	suite.Require().True(saved[4].CreationTime.Equal(links[0].CreationTime))
+	suite.Require().Equal("10udsm", links[0].Amount)
+	suite.Require().Equal("key", links[0].CreatorKey)
+
+	links, total, err = suite.db.GetAddressLinks(testAddress, types.NewPagination(4, 2))
+	suite.Require().NoError(err)
+	suite.Require().Equal(uint64(5), total)
+	suite.Require().Len(links, 1)
+	suite.Require().Equal(saved[0].URL, links[0].URL)
+
+	// Addresses without links should return an empty page
+	links, total, err = suite.db.GetAddressLinks("desmos1unknown", types.NewPagination(0, 2))
+	suite.Require().NoError(err)
+	suite.Require().Zero(total)
+	suite.Require().Empty(links)
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS created_links
+(
+    id            INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    link_url      TEXT      NOT NULL,
+    action        TEXT      NOT NULL,
+    address       TEXT      NOT NULL DEFAULT '',
+    amount        TEXT      NOT NULL DEFAULT '',
+    chain_type    TEXT      NOT NULL DEFAULT '',
+    creator_key   TEXT      NOT NULL DEFAULT '',
+    creation_time TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS created_links_address_index ON created_links (address, creation_time DESC);
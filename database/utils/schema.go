@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateSchema runs all the SQL files contained inside the schema folder of the given file system, in order,
+// to make sure that all the tables exist.
+// NOTE: The SQL files must be idempotent, since they are executed every time the database is opened
+func CreateSchema(db *sqlx.DB, schemaFS fs.FS) error {
+	files, err := fs.Glob(schemaFS, "schema/*.sql")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		stmt, err := fs.ReadFile(schemaFS, file)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Exec(string(stmt))
+		if err != nil {
+			return fmt.Errorf("error while running %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
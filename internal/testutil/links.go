@@ -0,0 +1,120 @@
+package testutil
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/desmos-labs/desmos/v6/app"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/authentication"
+	"github.com/desmos-labs/dpm-apis/cache"
+	"github.com/desmos-labs/dpm-apis/caerus"
+	"github.com/desmos-labs/dpm-apis/caerus/fake"
+	"github.com/desmos-labs/dpm-apis/ratelimit"
+	"github.com/desmos-labs/dpm-apis/routes/links"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	// CaerusAPIKey represents the API key accepted by the fake Caerus server
+	CaerusAPIKey = "caerus-api-key"
+
+	// APIKey represents the API key accepted by the routes, which is granted all the scopes
+	APIKey = "api-key"
+
+	// APIKeyID represents the identifier of APIKey
+	APIKeyID = "test"
+
+	// BaseURL represents the base URL of the links generated by the fake Caerus server
+	BaseURL = "https://dpm.test.link"
+)
+
+// TrackerStub is a links.Tracker that discards all the events
+type TrackerStub struct{}
+
+// Track implements links.Tracker
+func (TrackerStub) Track(*analytics.Event) {}
+
+// TestAddress sets up the Desmos Bech32 prefixes and returns a valid address that can be used within the tests
+func TestAddress() string {
+	app.SetupConfig(sdk.GetConfig())
+	return sdk.AccAddress(bytes.Repeat([]byte{1}, 20)).String()
+}
+
+// NewAuthenticator returns a new Authenticator instance that accepts only APIKey
+func NewAuthenticator() (*authentication.Authenticator, error) {
+	return authentication.NewAuthenticator(
+		[]*authentication.APIKey{
+			{ID: APIKeyID, Hash: authentication.HashKey(APIKey), Scopes: []string{authentication.ScopeAdmin}},
+		},
+		authentication.NewSessionsManager(&authentication.SessionsConfig{Secret: []byte("secret")}),
+	)
+}
+
+// NewDisabledLimiter returns a new Limiter instance that allows all the requests
+func NewDisabledLimiter() *ratelimit.Limiter {
+	cfg := ratelimit.DefaultConfig()
+	cfg.Disabled = true
+	return ratelimit.NewLimiter(cfg)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// FakeCaerus represents a fake Caerus server that is served in memory, along with the gRPC connection to it
+type FakeCaerus struct {
+	server *fake.Server
+	conn   *grpc.ClientConn
+}
+
+// StartFakeCaerus starts a new fake Caerus server that accepts only CaerusAPIKey and generates the links using
+// BaseURL. The returned server must be stopped at the end of the test
+func StartFakeCaerus() (*FakeCaerus, error) {
+	server := fake.NewServer(CaerusAPIKey, BaseURL)
+
+	conn, err := server.ServeInMemory()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FakeCaerus{server: server, conn: conn}, nil
+}
+
+// Client returns a new Caerus client that calls the fake server using the given API key
+func (f *FakeCaerus) Client(apiKey string) *caerus.Client {
+	cfg := caerus.DefaultConfig()
+	cfg.APIKey = apiKey
+	cfg.BranchKey = "branch-key"
+	return caerus.NewClient(cfg, f.conn)
+}
+
+// Stop closes the connection to the fake server and stops it
+func (f *FakeCaerus) Stop() error {
+	err := f.conn.Close()
+	f.server.Stop()
+	return err
+}
+
+// NewLinksRouter returns a new router exposing the links routes, which create the links using the given Caerus client
+// and store them inside the given database. The requests must be authenticated using APIKey, and are never rate limited
+func NewLinksRouter(caerusClient links.CaerusClient, db links.Database, tracker links.Tracker) (*gin.Engine, error) {
+	authenticator, err := NewAuthenticator()
+	if err != nil {
+		return nil, err
+	}
+
+	handler := links.NewHandler(
+		links.DefaultConfig(),
+		caerusClient,
+		cache.NewLinkConfigCache(cache.DefaultLinkConfigCacheConfig()),
+		db,
+		tracker,
+	)
+
+	router := gin.New()
+	router.Use(utils.RequestID())
+	links.Register(router, handler, authenticator, NewDisabledLimiter())
+	return router, nil
+}
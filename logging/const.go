@@ -0,0 +1,5 @@
+package logging
+
+const (
+	EnvLoggingFormat = "LOG_FORMAT"
+)
@@ -1,46 +1,179 @@
 package logging
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"time"
 
-	"github.com/desmos-labs/caerus/logging"
-	"github.com/desmos-labs/caerus/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/desmos-labs/dpm-apis/redaction"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	// FormatConsole represents the human-readable format, suited for the development
+	FormatConsole = "console"
+
+	// FormatJSON represents the format writing one JSON object per line, suited for the log aggregation
+	FormatJSON = "json"
 )
 
 func init() {
-	logLevelStr := utils.GetEnvOr(logging.EnvLoggingLevel, zerolog.InfoLevel.String())
-	logLevel, err := zerolog.ParseLevel(logLevelStr)
+	// Use the default configuration until Setup is called
+	log.Logger = newLogger(zerolog.InfoLevel, FormatConsole, redaction.NewRedactor(redaction.DefaultConfig()))
+}
+
+// Config contains the logging configuration
+type Config struct {
+	// Level represents the minimum level of the logged messages (e.g. "debug", "info", "warn")
+	Level string `yaml:"level"`
+
+	// Format represents the format of the logged messages (either FormatConsole or FormatJSON)
+	Format string `yaml:"format"`
+}
+
+// DefaultConfig returns the default Config instance
+func DefaultConfig() *Config {
+	return &Config{
+		Level:  zerolog.InfoLevel.String(),
+		Format: FormatConsole,
+	}
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found
+func (c *Config) Validate() error {
+	var errs []error
+
+	_, err := zerolog.ParseLevel(c.Level)
+	if err != nil || c.Level == "" {
+		errs = append(errs, fmt.Errorf("invalid level %q", c.Level))
+	}
+
+	if c.Format != FormatConsole && c.Format != FormatJSON {
+		errs = append(errs, fmt.Errorf("invalid format %q: must be either %s or %s", c.Format, FormatConsole, FormatJSON))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Setup configures the global logger based on the given configuration.
+// All the messages are redacted using the given redactor before being written
+func Setup(cfg *Config, redactor *redaction.Redactor) error {
+	err := cfg.Validate()
 	if err != nil {
-		panic(err)
+		return err
+	}
+
+	level, _ := zerolog.ParseLevel(cfg.Level)
+	log.Logger = newLogger(level, cfg.Format, redactor)
+	return nil
+}
+
+// newLogger returns a new logger that writes the messages having at least the given level using the given format,
+// after redacting them using the given redactor
+func newLogger(level zerolog.Level, format string, redactor *redaction.Redactor) zerolog.Logger {
+	var output io.Writer = os.Stderr
+	if format == FormatConsole {
+		output = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}
 	}
 
-	// Setup logging
-	log.Logger = zerolog.
-		New(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}).
-		Level(logLevel).
+	return zerolog.
+		New(&redactingWriter{output: output, redactor: redactor}).
+		Level(level).
 		With().Timestamp().
 		Logger()
 }
 
-// ZeroLog returns a Gin Handler function that logs endpoint calls using ZeroLog
-func ZeroLog() gin.HandlerFunc {
+// redactingWriter is a zerolog.LevelWriter that redacts the messages using the policy associated with their level
+// before writing them to the underlying output
+type redactingWriter struct {
+	output   io.Writer
+	redactor *redaction.Redactor
+}
+
+// Write implements zerolog.LevelWriter
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter
+func (w *redactingWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	_, err := io.WriteString(w.output, w.redactor.RedactLevel(level.String(), string(p)))
+	if err != nil {
+		return 0, err
+	}
+
+	// Report the length of the original message, since the redacted one might have a different one
+	return len(p), nil
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// ZeroLog returns a Gin Handler function that logs one access log line for each request handled, containing its
+// method, route, status, latency, response size, client IP, request ID and API key ID, as well as the errors that
+// occurred while handling it, if any.
+// The successful requests to the given ignored paths (e.g. the health checks) are not logged
+func ZeroLog(ignoredPaths ...string) gin.HandlerFunc {
+	ignored := make(map[string]bool, len(ignoredPaths))
+	for _, path := range ignoredPaths {
+		ignored[path] = true
+	}
+
 	return func(c *gin.Context) {
+		start := time.Now()
+
 		// Process request
 		c.Next()
 
-		// Log request
-		log.Trace().Str("path", c.Request.URL.Path).Msg("received request")
+		status := c.Writer.Status()
+		if ignored[c.Request.URL.Path] && status < http.StatusInternalServerError {
+			return
+		}
+
+		// Use the route template (e.g. /deep-links/:url) instead of the path, so that the lines of the same route
+		// can be easily grouped together
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
 
-		// Log errors
-		for _, err := range c.Errors {
-			log.Error().
-				Int("status", c.Writer.Status()).
-				Str("path", c.Request.URL.Path).
-				Msg(err.Error())
+		event := log.Info()
+		switch {
+		case status >= http.StatusInternalServerError:
+			event = log.Error()
+		case status >= http.StatusBadRequest:
+			event = log.Warn()
 		}
+
+		// The size is -1 when nothing has been written
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+
+		event = event.
+			Str("method", c.Request.Method).
+			Str("route", route).
+			Int("status", status).
+			Dur("latency_ms", time.Since(start)).
+			Int("bytes", size).
+			Str("client_ip", c.ClientIP()).
+			Str("request_id", utils.GetRequestID(c))
+
+		if apiKeyID := utils.GetAPIKeyID(c); apiKeyID != "" {
+			event = event.Str("api_key_id", apiKeyID)
+		}
+
+		if len(c.Errors) > 0 {
+			event = event.Strs("errors", c.Errors.Errors())
+		}
+
+		event.Msg("request handled")
 	}
 }
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/redaction"
+)
+
+const (
+	testAddress = "desmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+)
+
+func TestRedactingWriterTestSuite(t *testing.T) {
+	suite.Run(t, new(RedactingWriterTestSuite))
+}
+
+// RedactingWriterTestSuite tests the redaction of the logged messages
+type RedactingWriterTestSuite struct {
+	suite.Suite
+
+	redactor *redaction.Redactor
+	output   *bytes.Buffer
+}
+
+func (suite *RedactingWriterTestSuite) SetupTest() {
+	suite.redactor = redaction.NewRedactor(&redaction.Config{
+		Key:    "0123456789abcdef",
+		Mode:   redaction.ModeHash,
+		Levels: map[string]string{"debug": redaction.ModePlain, "error": redaction.ModeMask},
+	})
+	suite.output = &bytes.Buffer{}
+}
+
+// buildLogger returns a new logger writing the messages into the suite output using the given format
+func (suite *RedactingWriterTestSuite) buildLogger(format string) zerolog.Logger {
+	writer := &redactingWriter{output: suite.output, redactor: suite.redactor}
+	if format == FormatConsole {
+		writer.output = zerolog.ConsoleWriter{Out: suite.output, NoColor: true}
+	}
+	return zerolog.New(writer).Level(zerolog.DebugLevel)
+}
+
+func (suite *RedactingWriterTestSuite) TestJSON() {
+	logger := suite.buildLogger(FormatJSON)
+	logger.Info().Str("address", testAddress).Msg("creating link for " + testAddress)
+
+	line := suite.output.String()
+	suite.Require().NotContains(line, testAddress)
+	suite.Require().JSONEq(
+		`{"level":"info","address":"`+suite.redactor.HashAddress(testAddress)+`","message":"creating link for `+suite.redactor.HashAddress(testAddress)+`"}`,
+		line,
+	)
+}
+
+func (suite *RedactingWriterTestSuite) TestConsole() {
+	logger := suite.buildLogger(FormatConsole)
+	logger.Info().Str("address", testAddress).Msg("creating link")
+
+	line := suite.output.String()
+	suite.Require().NotContains(line, testAddress)
+	suite.Require().Contains(line, "address="+suite.redactor.HashAddress(testAddress))
+}
+
+func (suite *RedactingWriterTestSuite) TestLevelOverrides() {
+	logger := suite.buildLogger(FormatJSON)
+
+	logger.Debug().Str("address", testAddress).Send()
+	suite.Require().Contains(suite.output.String(), testAddress)
+	suite.output.Reset()
+
+	logger.Error().Str("address", testAddress).Send()
+	suite.Require().NotContains(suite.output.String(), testAddress)
+	suite.Require().Contains(suite.output.String(), `"address":"`+redaction.Redacted+`"`)
+	suite.output.Reset()
+
+	logger.Warn().Str("address", testAddress).Send()
+	suite.Require().Contains(suite.output.String(), `"address":"`+suite.redactor.HashAddress(testAddress)+`"`)
+}
+
+func (suite *RedactingWriterTestSuite) TestCredentials() {
+	for _, format := range []string{FormatJSON, FormatConsole} {
+		suite.output.Reset()
+
+		// Credentials should be masked even in the levels keeping the addresses in plain text
+		logger := suite.buildLogger(format)
+		logger.Debug().Str("authorization", "Bearer secret-token").Str("proxy", "Basic dXNlcjpwYXNz").Send()
+
+		line := suite.output.String()
+		suite.Require().NotContains(line, "secret-token", "credentials leaked using %s format", format)
+		suite.Require().NotContains(line, "dXNlcjpwYXNz", "credentials leaked using %s format", format)
+		suite.Require().Contains(line, "Bearer "+redaction.Redacted)
+	}
+}
+
+func (suite *RedactingWriterTestSuite) TestWriteReportsOriginalLength() {
+	writer := &redactingWriter{output: suite.output, redactor: suite.redactor}
+	message := []byte("address " + testAddress + "\n")
+
+	n, err := writer.Write(message)
+	suite.Require().NoError(err)
+	suite.Require().Equal(len(message), n)
+	suite.Require().NotContains(suite.output.String(), testAddress)
+}
@@ -7,76 +7,176 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/desmos-labs/caerus/analytics"
-	"github.com/desmos-labs/caerus/runner"
-	"github.com/desmos-labs/caerus/utils"
 	"github.com/desmos-labs/desmos/v6/app"
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
 
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/authentication"
+	"github.com/desmos-labs/dpm-apis/cache"
 	"github.com/desmos-labs/dpm-apis/caerus"
+	"github.com/desmos-labs/dpm-apis/config"
+	"github.com/desmos-labs/dpm-apis/cors"
+	"github.com/desmos-labs/dpm-apis/database"
 	"github.com/desmos-labs/dpm-apis/logging"
+	"github.com/desmos-labs/dpm-apis/metrics"
+	"github.com/desmos-labs/dpm-apis/ratelimit"
+	"github.com/desmos-labs/dpm-apis/redaction"
 	"github.com/desmos-labs/dpm-apis/routes"
+	authroutes "github.com/desmos-labs/dpm-apis/routes/auth"
+	docsroutes "github.com/desmos-labs/dpm-apis/routes/docs"
+	healthroutes "github.com/desmos-labs/dpm-apis/routes/health"
 	linksroutes "github.com/desmos-labs/dpm-apis/routes/links"
+	"github.com/desmos-labs/dpm-apis/tracing"
+	"github.com/desmos-labs/dpm-apis/utils"
 )
 
 func main() {
+	var configPath string
+
+	rootCmd := &cobra.Command{
+		Use:           "dpm-apis",
+		Short:         "Runs the DPM APIs server",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return errors.New(config.FormatError(err))
+			}
+
+			return startServer(cfg)
+		},
+	}
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", os.Getenv(config.EnvConfigFile), "path of the YAML configuration file")
+	rootCmd.AddCommand(newConfigCmd(&configPath))
+
+	err := rootCmd.Execute()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// startServer starts the API server using the given configuration, blocking until it is shut down
+func startServer(cfg *config.Config) error {
+	redactor := redaction.NewRedactor(cfg.Redaction)
+	err := logging.Setup(cfg.Logging, redactor)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Redaction.Key == "" {
+		log.Warn().Msgf("Missing %s: using a random key to hash the addresses, so the hashes will change after a restart", redaction.EnvRedactionKey)
+	}
+
 	// Setup Cosmos-related stuff
 	app.SetupConfig(sdk.GetConfig())
 
+	// Setup the tracing before building the clients so that they can be instrumented
+	tracer := tracing.SetupFromEnvVariables()
+
 	// Build the clients
-	caerusClient := caerus.NewClientFromEnvVariables()
+	caerusClient := caerus.NewClientFromConfig(cfg.Caerus)
+	linkConfigCache := cache.NewLinkConfigCacheFromEnvVariables()
+	db := database.NewFromEnvVariables()
+	authenticator := authentication.NewAuthenticatorFromEnvVariables()
+	rateLimiter := ratelimit.NewLimiterFromEnvVariables()
+	tracker, err := analytics.Setup(cfg.Analytics, redactor)
+	if err != nil {
+		return fmt.Errorf("error while setting up the analytics: %w", err)
+	}
 
-	// Setup the CORS
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization")
+	// Setup the metrics
+	metrics.RegisterCache("link_config", linkConfigCache.Stats)
 
 	// Build the Gin server
 	router := gin.New()
-	router.Use(logging.ZeroLog(), gin.Recovery(), cors.New(corsConfig))
+
+	// Only trust the configured proxies, so that clients cannot pick their own IP (and thus their own rate
+	// limiting bucket) by setting the X-Forwarded-For header
+	err = router.SetTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("error while setting the trusted proxies: %w", err)
+	}
+
+	router.Use(
+		utils.RequestID(),
+		tracer.Middleware("/metrics", healthroutes.LivenessPath, healthroutes.ReadinessPath),
+		logging.ZeroLog("/metrics", healthroutes.LivenessPath, healthroutes.ReadinessPath),
+		metrics.Prometheus(),
+		gin.CustomRecovery(func(c *gin.Context, recovered any) {
+			utils.HandleError(c, fmt.Errorf("panic: %v", recovered))
+		}),
+		cors.Middleware(cfg.CORS),
+		utils.RequestTimeout(cfg.Server.RequestTimeout),
+	)
+	router.NoRoute(func(c *gin.Context) {
+		utils.HandleError(c, utils.WrapErr(http.StatusNotFound, utils.ErrCodeNotFound, "route not found"))
+	})
 
 	// Build the routes context
+	draining := &atomic.Bool{}
 	ctx := routes.Context{
-		Router: router,
-		Caerus: caerusClient,
+		Router:          router,
+		Caerus:          caerusClient,
+		LinkConfigCache: linkConfigCache,
+		Database:        db,
+		Authenticator:   authenticator,
+		RateLimiter:     rateLimiter,
+		Analytics:       tracker,
+		Draining:        draining,
 	}
 
 	// Register the routes
+	router.GET("/metrics", authenticator.RequireScope(authentication.ScopeAdmin), metrics.Handler())
+	healthroutes.RegisterWithContext(ctx)
+	authroutes.RegisterWithContext(ctx)
 	linksroutes.RegisterWithContext(ctx)
+	docsroutes.RegisterWithContext(ctx)
 
 	// Build the HTTP server to be able to shut it down if needed
-	runningAddress := utils.GetEnvOr(runner.EnvServerAddress, "0.0.0.0")
-	runningPort := utils.GetEnvOr(runner.EnvServerPort, "3000")
 	httpServer := &http.Server{
-		Addr:              fmt.Sprintf("%s:%s", runningAddress, runningPort),
+		Addr:              cfg.Server.ListenAddress(),
 		Handler:           router,
-		ReadHeaderTimeout: time.Minute,
-		ReadTimeout:       time.Minute,
-		WriteTimeout:      time.Minute,
+		ReadHeaderTimeout: cfg.Server.ReadTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
 	}
 
 	// Listen for and trap any OS signal to gracefully shutdown and exit
-	go trapSignal(httpServer)
+	var shutdown sync.WaitGroup
+	shutdown.Add(1)
+	go func() {
+		defer shutdown.Done()
+		trapSignal(httpServer, draining, cfg.Server, db, tracker, tracer)
+	}()
 
 	// Start the HTTP server
-	// Block main process (signal capture will call WaitGroup's Done)
 	log.Info().Str("address", httpServer.Addr).Msg("Starting API server")
-	err := httpServer.ListenAndServe()
+	err = httpServer.ListenAndServe()
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
-		panic(err)
+		return err
 	}
+
+	// Block main process until the cleanup has completed, so that the pending analytics events and spans are sent
+	shutdown.Wait()
+	return nil
 }
 
-// trapSignal traps the stops signals to gracefully shut down the server
-func trapSignal(httpServer *http.Server) {
-	// Wait for interrupt signal to gracefully shut down the server with
-	// a timeout of 5 seconds.
+// trapSignal traps the stops signals to gracefully shut down the server.
+// Before shutting down, the server is marked as draining for the configured delay so that the readiness probe fails
+// and the load balancer stops sending new traffic to it
+func trapSignal(httpServer *http.Server, draining *atomic.Bool, serverCfg *config.ServerConfig, db database.Database, tracker *analytics.Tracker, tracer *tracing.Tracing) {
+	// Wait for interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 
 	// Kill (no param) default send syscall.SIGTERM
@@ -84,11 +184,15 @@ func trapSignal(httpServer *http.Server) {
 	// Kill -9 is syscall.SIGKILL but can't be caught, so don't need to add it
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	log.Info().Dur("delay", serverCfg.DrainDelay).Msg("Draining API server")
+	draining.Store(true)
+	time.Sleep(serverCfg.DrainDelay)
+
 	log.Debug().Msg("Shutting down API server")
 
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// The context is used to inform the server how long it has to finish
+	// the requests it is currently handling
+	ctx, cancel := context.WithTimeout(context.Background(), serverCfg.ShutdownTimeout)
 	defer cancel()
 
 	if err := httpServer.Shutdown(ctx); err != nil {
@@ -97,6 +201,27 @@ func trapSignal(httpServer *http.Server) {
 
 	log.Info().Msg("API server shutdown")
 
-	// Perform the cleanup of other things
-	analytics.Stop()
+	// Perform the cleanup of other things. Each flush gets its own timeout, since the server shutdown might
+	// have used up the previous one
+	err := stopWithTimeout(tracker.Stop, serverCfg.ShutdownTimeout)
+	if err != nil {
+		log.Error().Err(err).Msg("error while stopping the analytics")
+	}
+
+	err = stopWithTimeout(tracer.Stop, serverCfg.ShutdownTimeout)
+	if err != nil {
+		log.Error().Err(err).Msg("error while stopping the tracing")
+	}
+
+	err = db.Close()
+	if err != nil {
+		log.Error().Err(err).Msg("error while closing the database")
+	}
+}
+
+// stopWithTimeout calls the given stop function using a new context that expires after the given timeout
+func stopWithTimeout(stop func(ctx context.Context) error, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return stop(ctx)
 }
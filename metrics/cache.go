@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/desmos-labs/dpm-apis/cache"
+)
+
+// cacheCollector exposes the usage statistics of a cache as Prometheus metrics
+type cacheCollector struct {
+	stats func() cache.Stats
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	size      *prometheus.Desc
+}
+
+// RegisterCache registers the metrics exposing the usage statistics returned by the given function,
+// labelling them with the given cache name
+func RegisterCache(name string, stats func() cache.Stats) {
+	labels := prometheus.Labels{"cache": name}
+	prometheus.MustRegister(&cacheCollector{
+		stats:     stats,
+		hits:      prometheus.NewDesc(namespace+"_cache_hits_total", "Total number of cache hits", nil, labels),
+		misses:    prometheus.NewDesc(namespace+"_cache_misses_total", "Total number of cache misses", nil, labels),
+		evictions: prometheus.NewDesc(namespace+"_cache_evictions_total", "Total number of entries evicted from the cache", nil, labels),
+		size:      prometheus.NewDesc(namespace+"_cache_size", "Number of entries currently stored inside the cache", nil, labels),
+	})
+}
+
+// Describe implements prometheus.Collector
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.size
+}
+
+// Collect implements prometheus.Collector
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(stats.Size))
+}
@@ -0,0 +1,6 @@
+package metrics
+
+const (
+	// namespace represents the namespace of all the metrics exposed by the APIs
+	namespace = "dpm_apis"
+)
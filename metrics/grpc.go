@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	caerusRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "caerus",
+		Name:      "requests_total",
+		Help:      "Total number of gRPC calls performed to Caerus, by method and status code",
+	}, []string{"method", "code"})
+
+	caerusRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "caerus",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the gRPC calls performed to Caerus, by method",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+// UnaryClientInterceptor returns a gRPC client interceptor that records the number, the duration and the
+// resulting status code of each call performed to Caerus
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		caerusRequestsTotal.WithLabelValues(method, status.Code(err).String()).Inc()
+		caerusRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,315 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"strings"
+
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	MinSize   = 64
+	MaxSize   = 2048
+	MaxMargin = 16
+
+	// MaxContentLength represents the max length, in bytes, of the contents whose QR codes are guaranteed to fit
+	// inside the images having valid options
+	MaxContentLength = 128
+
+	// logoRatio represents the max portion of the QR code width that can be covered by the logo
+	logoRatio = 5
+)
+
+var (
+	// maxModules contains, for each error correction level, the number of modules of the largest QR code
+	maxModules = computeMaxModules()
+)
+
+// computeMaxModules returns the number of modules of the QR code of a MaxContentLength bytes content for each
+// error correction level
+func computeMaxModules() map[goqrcode.RecoveryLevel]int {
+	content := strings.Repeat("a", MaxContentLength)
+
+	modules := make(map[goqrcode.RecoveryLevel]int)
+	for _, level := range []goqrcode.RecoveryLevel{goqrcode.Low, goqrcode.Medium, goqrcode.High, goqrcode.Highest} {
+		code, err := goqrcode.New(content, level)
+		if err != nil {
+			panic(err)
+		}
+		code.DisableBorder = true
+		modules[level] = len(code.Bitmap())
+	}
+	return modules
+}
+
+// Options contains the options used to render a QR code
+type Options struct {
+	// Format represents the image format of the QR code (either FormatPNG or FormatSVG)
+	Format string
+
+	// Size represents the width and height of the QR code, in pixels
+	Size int
+
+	// Margin represents the size of the quiet zone around the QR code, in modules
+	Margin int
+
+	// Level represents the error correction level of the QR code
+	Level goqrcode.RecoveryLevel
+
+	// Logo represents the optional image that should be drawn at the center of the QR code
+	Logo image.Image
+}
+
+// DefaultOptions returns the default Options instance
+func DefaultOptions() *Options {
+	return &Options{
+		Format: FormatPNG,
+		Size:   256,
+		Margin: 4,
+		Level:  goqrcode.Medium,
+	}
+}
+
+// Validate checks whether the options are valid or not
+func (o *Options) Validate() error {
+	if o.Format != FormatPNG && o.Format != FormatSVG {
+		return fmt.Errorf("invalid format: must be either %s or %s", FormatPNG, FormatSVG)
+	}
+
+	if o.Size < MinSize || o.Size > MaxSize {
+		return fmt.Errorf("invalid size: must be between %d and %d", MinSize, MaxSize)
+	}
+
+	if o.Margin < 0 || o.Margin > MaxMargin {
+		return fmt.Errorf("invalid margin: must be between 0 and %d", MaxMargin)
+	}
+
+	// Make sure that the QR code fits inside the image whatever its content, so that the options can be rejected
+	// before the content is known
+	requiredSize := maxModules[o.level()] + 2*o.Margin
+	if o.Size < requiredSize {
+		return fmt.Errorf("invalid size: the QR code requires at least %d pixels with the given margin", requiredSize)
+	}
+
+	return nil
+}
+
+// level returns the error correction level used to render the QR code.
+// When drawing a logo part of the code gets covered, so the highest level is used to keep it readable
+func (o *Options) level() goqrcode.RecoveryLevel {
+	if o.Logo != nil {
+		return goqrcode.Highest
+	}
+	return o.Level
+}
+
+// ContentType returns the MIME type of the images rendered with the given options
+func (o *Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// ParseLevel parses the given value as an error correction level.
+// It accepts both the standard letters (L, M, Q, H) and their names (low, medium, high, highest)
+func ParseLevel(value string) (goqrcode.RecoveryLevel, error) {
+	switch strings.ToLower(value) {
+	case "l", "low":
+		return goqrcode.Low, nil
+	case "m", "medium":
+		return goqrcode.Medium, nil
+	case "q", "high":
+		return goqrcode.High, nil
+	case "h", "highest":
+		return goqrcode.Highest, nil
+	default:
+		return goqrcode.Medium, fmt.Errorf("invalid error correction level: %s", value)
+	}
+}
+
+// LoadLogo reads the PNG image stored at the given path so that it can be used as a QR code logo
+func LoadLogo(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// Encode renders the given content as a QR code using the given options
+func Encode(content string, opts *Options) ([]byte, error) {
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := goqrcode.New(content, opts.level())
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+
+	grid := newGrid(code.Bitmap(), opts.Margin, opts.Size)
+	if grid.scale < 1 {
+		return nil, fmt.Errorf("invalid size: the QR code requires at least %d pixels", grid.modules)
+	}
+
+	if opts.Format == FormatSVG {
+		return renderSVG(grid, opts)
+	}
+	return renderPNG(grid, opts)
+}
+
+// grid contains the data needed to draw the modules of a QR code inside an image
+type grid struct {
+	bitmap  [][]bool
+	margin  int
+	modules int
+	scale   int
+	offset  int
+}
+
+func newGrid(bitmap [][]bool, margin int, size int) *grid {
+	modules := len(bitmap) + 2*margin
+	scale := size / modules
+	return &grid{
+		bitmap:  bitmap,
+		margin:  margin,
+		modules: modules,
+		scale:   scale,
+		offset:  (size - scale*modules) / 2,
+	}
+}
+
+// logoBounds returns the area of the image that should be covered by the logo
+func logoBounds(size int) image.Rectangle {
+	logoSize := size / logoRatio
+	start := (size - logoSize) / 2
+	return image.Rect(start, start, start+logoSize, start+logoSize)
+}
+
+// renderPNG renders the given grid as a PNG image
+func renderPNG(grid *grid, opts *Options) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, opts.Size, opts.Size))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for y, row := range grid.bitmap {
+		for x, set := range row {
+			if !set {
+				continue
+			}
+
+			startX := grid.offset + (x+grid.margin)*grid.scale
+			startY := grid.offset + (y+grid.margin)*grid.scale
+			rect := image.Rect(startX, startY, startX+grid.scale, startY+grid.scale)
+			draw.Draw(img, rect, image.Black, image.Point{}, draw.Src)
+		}
+	}
+
+	if opts.Logo != nil {
+		bounds := logoBounds(opts.Size)
+		draw.Draw(img, bounds, image.White, image.Point{}, draw.Src)
+		drawScaled(img, bounds, opts.Logo)
+	}
+
+	var buffer bytes.Buffer
+	err := png.Encode(&buffer, img)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// drawScaled draws the given source image inside the given bounds of the destination image,
+// scaling it using the nearest-neighbor algorithm
+func drawScaled(dst draw.Image, bounds image.Rectangle, src image.Image) {
+	srcBounds := src.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			srcX := srcBounds.Min.X + x*srcBounds.Dx()/bounds.Dx()
+			srcY := srcBounds.Min.Y + y*srcBounds.Dy()/bounds.Dy()
+
+			// Blend the logo with the background to properly support transparency
+			pixel := color.RGBAModel.Convert(src.At(srcX, srcY)).(color.RGBA)
+			dstX, dstY := bounds.Min.X+x, bounds.Min.Y+y
+			background := color.RGBAModel.Convert(dst.At(dstX, dstY)).(color.RGBA)
+			dst.Set(dstX, dstY, blend(background, pixel))
+		}
+	}
+}
+
+// blend returns the color obtained by drawing the given (alpha-premultiplied) source over the given background
+func blend(background color.RGBA, source color.RGBA) color.RGBA {
+	alpha := 255 - uint32(source.A)
+	return color.RGBA{
+		R: uint8(uint32(source.R) + uint32(background.R)*alpha/255),
+		G: uint8(uint32(source.G) + uint32(background.G)*alpha/255),
+		B: uint8(uint32(source.B) + uint32(background.B)*alpha/255),
+		A: 255,
+	}
+}
+
+// renderSVG renders the given grid as an SVG image
+func renderSVG(grid *grid, opts *Options) ([]byte, error) {
+	var path strings.Builder
+	for y, row := range grid.bitmap {
+		for x, set := range row {
+			if set {
+				path.WriteString(fmt.Sprintf("M%d %dh1v1h-1z", x+grid.margin, y+grid.margin))
+			}
+		}
+	}
+
+	// Use the view box to scale the modules to the requested size
+	viewBoxSize := float64(opts.Size) / float64(grid.scale)
+	viewBoxOffset := float64(grid.offset) / float64(grid.scale)
+
+	var svg strings.Builder
+	svg.WriteString(fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="%g %g %g %g" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, -viewBoxOffset, -viewBoxOffset, viewBoxSize, viewBoxSize,
+	))
+	svg.WriteString(fmt.Sprintf(
+		`<rect x="%g" y="%g" width="%g" height="%g" fill="#ffffff"/>`,
+		-viewBoxOffset, -viewBoxOffset, viewBoxSize, viewBoxSize,
+	))
+	svg.WriteString(fmt.Sprintf(`<path d="%s" fill="#000000"/>`, path.String()))
+
+	if opts.Logo != nil {
+		var logo bytes.Buffer
+		err := png.Encode(&logo, opts.Logo)
+		if err != nil {
+			return nil, err
+		}
+
+		logoSize := viewBoxSize / logoRatio
+		logoStart := (viewBoxSize-logoSize)/2 - viewBoxOffset
+		svg.WriteString(fmt.Sprintf(
+			`<rect x="%g" y="%g" width="%g" height="%g" fill="#ffffff"/>`,
+			logoStart, logoStart, logoSize, logoSize,
+		))
+		svg.WriteString(fmt.Sprintf(
+			`<image x="%g" y="%g" width="%g" height="%g" href="data:image/png;base64,%s"/>`,
+			logoStart, logoStart, logoSize, logoSize, base64.StdEncoding.EncodeToString(logo.Bytes()),
+		))
+	}
+
+	svg.WriteString(`</svg>`)
+	return []byte(svg.String()), nil
+}
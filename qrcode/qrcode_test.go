@@ -0,0 +1,214 @@
+package qrcode_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	goqrcode "github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/qrcode"
+)
+
+const (
+	testLink = "https://dpm.link/3f9a1c0b7d2e4a56"
+)
+
+func TestQRCodeTestSuite(t *testing.T) {
+	suite.Run(t, new(QRCodeTestSuite))
+}
+
+// QRCodeTestSuite tests the validation of the options and the rendering of the QR codes
+type QRCodeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *QRCodeTestSuite) TestValidate() {
+	testCases := []struct {
+		name     string
+		malleate func(opts *qrcode.Options)
+		expError string
+	}{
+		{
+			name:     "default options are valid",
+			malleate: func(opts *qrcode.Options) {},
+		},
+		{
+			name:     "invalid format",
+			malleate: func(opts *qrcode.Options) { opts.Format = "jpg" },
+			expError: "invalid format",
+		},
+		{
+			name:     "size below the min",
+			malleate: func(opts *qrcode.Options) { opts.Size = qrcode.MinSize - 1 },
+			expError: "invalid size: must be between",
+		},
+		{
+			name:     "size above the max",
+			malleate: func(opts *qrcode.Options) { opts.Size = qrcode.MaxSize + 1 },
+			expError: "invalid size: must be between",
+		},
+		{
+			name:     "negative margin",
+			malleate: func(opts *qrcode.Options) { opts.Margin = -1 },
+			expError: "invalid margin",
+		},
+		{
+			name:     "margin above the max",
+			malleate: func(opts *qrcode.Options) { opts.Margin = qrcode.MaxMargin + 1 },
+			expError: "invalid margin",
+		},
+		{
+			name: "min size with the lowest level",
+			malleate: func(opts *qrcode.Options) {
+				opts.Size = qrcode.MinSize
+				opts.Level = goqrcode.Low
+			},
+		},
+		{
+			name: "min size too small for the highest level",
+			malleate: func(opts *qrcode.Options) {
+				opts.Size = qrcode.MinSize
+				opts.Level = goqrcode.Highest
+			},
+			expError: "requires at least 69 pixels",
+		},
+		{
+			name: "min size too small for the logo",
+			malleate: func(opts *qrcode.Options) {
+				opts.Size = qrcode.MinSize
+				opts.Logo = image.NewRGBA(image.Rect(0, 0, 8, 8))
+			},
+			expError: "requires at least 69 pixels",
+		},
+		{
+			name: "min size too small for the max margin",
+			malleate: func(opts *qrcode.Options) {
+				opts.Size = qrcode.MinSize
+				opts.Margin = qrcode.MaxMargin
+			},
+			expError: "requires at least",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			opts := qrcode.DefaultOptions()
+			tc.malleate(opts)
+
+			err := opts.Validate()
+			if tc.expError == "" {
+				suite.Require().NoError(err)
+			} else {
+				suite.Require().ErrorContains(err, tc.expError)
+			}
+		})
+	}
+}
+
+func (suite *QRCodeTestSuite) TestEncode_PNG() {
+	opts := qrcode.DefaultOptions()
+	opts.Size = 300
+
+	bz, err := qrcode.Encode(testLink, opts)
+	suite.Require().NoError(err)
+	suite.Require().Equal("image/png", opts.ContentType())
+
+	img, err := png.Decode(bytes.NewReader(bz))
+	suite.Require().NoError(err)
+	suite.Require().Equal(image.Rect(0, 0, 300, 300), img.Bounds())
+
+	// The corners should be part of the quiet zone
+	r, g, b, _ := img.At(0, 0).RGBA()
+	suite.Require().Equal([3]uint32{0xffff, 0xffff, 0xffff}, [3]uint32{r, g, b})
+}
+
+func (suite *QRCodeTestSuite) TestEncode_PNGWithLogo() {
+	logo := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range logo.Pix {
+		logo.Pix[i] = 0xff
+	}
+
+	opts := qrcode.DefaultOptions()
+	opts.Logo = logo
+
+	bz, err := qrcode.Encode(testLink, opts)
+	suite.Require().NoError(err)
+
+	img, err := png.Decode(bytes.NewReader(bz))
+	suite.Require().NoError(err)
+	suite.Require().Equal(image.Rect(0, 0, opts.Size, opts.Size), img.Bounds())
+}
+
+func (suite *QRCodeTestSuite) TestEncode_SVG() {
+	opts := qrcode.DefaultOptions()
+	opts.Format = qrcode.FormatSVG
+	opts.Size = 512
+
+	bz, err := qrcode.Encode(testLink, opts)
+	suite.Require().NoError(err)
+	suite.Require().Equal("image/svg+xml", opts.ContentType())
+
+	svg := string(bz)
+	suite.Require().True(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="512" height="512"`))
+	suite.Require().True(strings.HasSuffix(strings.TrimSpace(svg), "</svg>"))
+	suite.Require().Contains(svg, "<path")
+}
+
+func (suite *QRCodeTestSuite) TestEncode_InvalidOptions() {
+	opts := qrcode.DefaultOptions()
+	opts.Size = qrcode.MaxSize + 1
+
+	_, err := qrcode.Encode(testLink, opts)
+	suite.Require().Error(err)
+}
+
+func (suite *QRCodeTestSuite) TestEncode_MaxContentLength() {
+	content := strings.Repeat("a", qrcode.MaxContentLength)
+
+	// The longest content should fit inside the smallest image accepted for each level and margin
+	levels := []goqrcode.RecoveryLevel{goqrcode.Low, goqrcode.Medium, goqrcode.High, goqrcode.Highest}
+	for _, level := range levels {
+		for margin := 0; margin <= qrcode.MaxMargin; margin++ {
+			opts := qrcode.DefaultOptions()
+			opts.Level = level
+			opts.Margin = margin
+
+			opts.Size = qrcode.MinSize
+			for opts.Validate() != nil {
+				opts.Size++
+			}
+
+			_, err := qrcode.Encode(content, opts)
+			suite.Require().NoError(err, "level %d, margin %d, size %d", level, margin, opts.Size)
+		}
+	}
+}
+
+func (suite *QRCodeTestSuite) TestParseLevel() {
+	testCases := []struct {
+		value     string
+		expLevel  goqrcode.RecoveryLevel
+		shouldErr bool
+	}{
+		{value: "L", expLevel: goqrcode.Low},
+		{value: "medium", expLevel: goqrcode.Medium},
+		{value: "q", expLevel: goqrcode.High},
+		{value: "HIGHEST", expLevel: goqrcode.Highest},
+		{value: "x", shouldErr: true},
+	}
+
+	for _, tc := range testCases {
+		level, err := qrcode.ParseLevel(tc.value)
+		if tc.shouldErr {
+			suite.Require().Error(err, tc.value)
+			continue
+		}
+		suite.Require().NoError(err, tc.value)
+		suite.Require().Equal(tc.expLevel, level, tc.value)
+	}
+}
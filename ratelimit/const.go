@@ -0,0 +1,7 @@
+package ratelimit
+
+const (
+	EnvRateLimitDisabled = "RATE_LIMIT_DISABLED"
+	EnvRateLimitPolicies = "RATE_LIMIT_POLICIES"
+	EnvRateLimitMaxKeys  = "RATE_LIMIT_MAX_KEYS"
+)
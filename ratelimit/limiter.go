@@ -0,0 +1,258 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	caerusutils "github.com/desmos-labs/caerus/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/desmos-labs/dpm-apis/cache"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+// Config contains the configuration of a Limiter
+type Config struct {
+	// Disabled tells whether the rate limiting is disabled and all requests should be allowed
+	Disabled bool
+
+	// MaxKeys represents the maximum number of clients tracked at the same time
+	MaxKeys int
+
+	// Policies contains the rate limiting policies indexed by their name
+	Policies map[string]*Policy
+}
+
+// DefaultConfig returns the default Config instance
+func DefaultConfig() *Config {
+	return &Config{
+		MaxKeys:  100_000,
+		Policies: DefaultPolicies(),
+	}
+}
+
+// ReadConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// The policies set using EnvRateLimitPolicies override the default ones having the same name.
+// It returns an error if any value is not valid
+func ReadConfigFromEnvVariables() (*Config, error) {
+	cfg := DefaultConfig()
+
+	disabled, err := strconv.ParseBool(caerusutils.GetEnvOr(EnvRateLimitDisabled, "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", EnvRateLimitDisabled, err)
+	}
+	cfg.Disabled = disabled
+
+	cfg.MaxKeys, err = utils.ParsePositiveIntEnvOr(EnvRateLimitMaxKeys, cfg.MaxKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if policiesJSON := caerusutils.GetEnvOr(EnvRateLimitPolicies, ""); policiesJSON != "" {
+		var policies map[string]*Policy
+		err = json.Unmarshal([]byte(policiesJSON), &policies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", EnvRateLimitPolicies, err)
+		}
+
+		for name, policy := range policies {
+			cfg.Policies[name] = policy
+		}
+	}
+
+	for name, policy := range cfg.Policies {
+		err = policy.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s rate limiting policy: %s", name, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// NewConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// It panics if any value is not valid
+func NewConfigFromEnvVariables() *Config {
+	cfg, err := ReadConfigFromEnvVariables()
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// bucket represents a token bucket associated with a single client
+type bucket struct {
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// Limiter allows to limit the rate of the requests performed by each client using the token bucket algorithm
+type Limiter struct {
+	cfg *Config
+
+	mu      sync.Mutex
+	buckets *cache.LRU[string, *bucket]
+}
+
+// NewLimiter returns a new Limiter instance using the given configuration
+func NewLimiter(cfg *Config) *Limiter {
+	if cfg.Disabled {
+		log.Warn().Msg("Rate limiting is disabled: all the requests will be allowed")
+	}
+
+	return &Limiter{
+		cfg:     cfg,
+		buckets: cache.NewLRU[string, *bucket](cfg.MaxKeys),
+	}
+}
+
+// NewLimiterFromEnvVariables returns a new Limiter instance reading the configuration from the env variables
+func NewLimiterFromEnvVariables() *Limiter {
+	return NewLimiter(NewConfigFromEnvVariables())
+}
+
+// Limit returns a Gin handler function that limits the rate of the requests using the policy having the given name.
+// It must be registered after the authentication middlewares so that the clients can be identified by their API key.
+// It panics if no policy with the given name exists
+func (l *Limiter) Limit(policyName string) gin.HandlerFunc {
+	l.getPolicy(policyName)
+
+	return func(c *gin.Context) {
+		if !l.Take(c, policyName, 1) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Take takes the given number of tokens from the bucket of the client that performed the request associated with
+// the given context, using the policy having the given name. It allows to limit the requests whose cost is known only
+// after reading them (e.g. the number of links inside a batch).
+// If the tokens cannot be taken, the error response is written and false is returned. Requests costing more than the
+// policy burst are always rejected, since they could never be allowed.
+// It panics if no policy with the given name exists
+func (l *Limiter) Take(c *gin.Context, policyName string, tokens int) bool {
+	policy := l.getPolicy(policyName)
+	if l.cfg.Disabled {
+		return true
+	}
+
+	key := fmt.Sprintf("%s/%s", policyName, getClientKey(c, policy.KeyBy))
+	res := l.take(key, policy, tokens, time.Now())
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(policy.Burst))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(res.remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.resetAfter)))
+
+	if tokens > policy.Burst {
+		utils.HandleError(c, utils.WrapErr(http.StatusTooManyRequests, utils.ErrCodeRateLimited, fmt.Sprintf("request exceeds the rate limit: at most %d allowed at once", policy.Burst)))
+		return false
+	}
+
+	if !res.allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.retryAfter)))
+		utils.HandleError(c, utils.WrapErr(http.StatusTooManyRequests, utils.ErrCodeRateLimited, "rate limit exceeded"))
+		return false
+	}
+
+	return true
+}
+
+// getPolicy returns the policy having the given name, panicking if it does not exist
+func (l *Limiter) getPolicy(policyName string) *Policy {
+	policy, found := l.cfg.Policies[policyName]
+	if !found {
+		panic(fmt.Errorf("rate limiting policy %s not found", policyName))
+	}
+	return policy
+}
+
+// takeResult contains the result of trying to take some tokens from a bucket
+type takeResult struct {
+	// allowed tells whether the request is allowed or not
+	allowed bool
+
+	// remaining represents the number of tokens that can still be taken immediately
+	remaining int
+
+	// resetAfter represents the time after which the bucket will be full again
+	resetAfter time.Duration
+
+	// retryAfter represents how long the client should wait before performing a new request, if not allowed
+	retryAfter time.Duration
+}
+
+// take tries to take the given number of tokens from the bucket associated with the given key.
+// No token is taken if the bucket does not contain enough of them
+func (l *Limiter) take(key string, policy *Policy, tokens int, now time.Time) takeResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, found := l.buckets.Get(key)
+	if !found {
+		b = &bucket{tokens: float64(policy.Burst), lastUpdate: now}
+	}
+
+	// Refill the bucket based on the time elapsed since the last request
+	elapsed := now.Sub(b.lastUpdate).Seconds()
+	b.tokens = math.Min(float64(policy.Burst), b.tokens+elapsed*policy.rate())
+	b.lastUpdate = now
+
+	var res takeResult
+	if b.tokens >= float64(tokens) {
+		b.tokens -= float64(tokens)
+		res.allowed = true
+	} else {
+		res.retryAfter = secondsToDuration((float64(tokens) - b.tokens) / policy.rate())
+	}
+	res.remaining = int(b.tokens)
+	res.resetAfter = secondsToDuration((float64(policy.Burst) - b.tokens) / policy.rate())
+
+	// A bucket that has not been used for the whole refill time is full again, so there is no need to keep it
+	l.buckets.Set(key, b, policy.refillTime())
+
+	return res
+}
+
+// getClientKey returns the key identifying the client that performed the request associated with the given context
+func getClientKey(c *gin.Context, keyBy KeyType) string {
+	switch keyBy {
+	case KeyByIP:
+		return "ip:" + c.ClientIP()
+	case KeyByClientAndIP:
+		return getClientIdentifier(c) + "/ip:" + c.ClientIP()
+	default:
+		return getClientIdentifier(c)
+	}
+}
+
+// getClientIdentifier returns the identifier of the client that performed the request associated with the given
+// context, falling back to its IP if the request is not authenticated
+func getClientIdentifier(c *gin.Context) string {
+	if keyID := utils.GetAPIKeyID(c); keyID != "" {
+		return "key:" + keyID
+	}
+	if address := utils.GetWalletAddress(c); address != "" {
+		return "wallet:" + address
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// secondsToDuration returns the duration corresponding to the given number of seconds
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// ceilSeconds returns the given duration as a number of seconds, rounded up
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,153 @@
+package ratelimit_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/ratelimit"
+)
+
+const (
+	testPolicy = "test"
+)
+
+func TestLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(LimiterTestSuite))
+}
+
+// LimiterTestSuite tests the token bucket rate limiting
+type LimiterTestSuite struct {
+	suite.Suite
+
+	limiter *ratelimit.Limiter
+}
+
+func (suite *LimiterTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *LimiterTestSuite) SetupTest() {
+	cfg := ratelimit.DefaultConfig()
+	cfg.Policies[testPolicy] = ratelimit.NewPolicy(1, time.Hour, 5, ratelimit.KeyByIP)
+	suite.limiter = ratelimit.NewLimiter(cfg)
+}
+
+// take takes the given number of tokens for a request, returning whether it has been allowed and its response
+func (suite *LimiterTestSuite) take(tokens int) (bool, *httptest.ResponseRecorder) {
+	res := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(res)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	return suite.limiter.Take(c, testPolicy, tokens), res
+}
+
+func (suite *LimiterTestSuite) TestTake() {
+	allowed, res := suite.take(3)
+	suite.Require().True(allowed)
+	suite.Require().Equal("5", res.Header().Get("X-RateLimit-Limit"))
+	suite.Require().Equal("2", res.Header().Get("X-RateLimit-Remaining"))
+
+	// No token should be taken if the bucket does not contain enough of them
+	allowed, res = suite.take(3)
+	suite.Require().False(allowed)
+	suite.Require().Equal(http.StatusTooManyRequests, res.Code)
+	suite.Require().NotEmpty(res.Header().Get("Retry-After"))
+
+	allowed, res = suite.take(2)
+	suite.Require().True(allowed)
+	suite.Require().Equal("0", res.Header().Get("X-RateLimit-Remaining"))
+}
+
+func (suite *LimiterTestSuite) TestTake_MoreThanBurst() {
+	allowed, res := suite.take(6)
+	suite.Require().False(allowed)
+	suite.Require().Equal(http.StatusTooManyRequests, res.Code)
+	suite.Require().Empty(res.Header().Get("Retry-After"))
+
+	// The rejected request should not consume any token
+	allowed, _ = suite.take(5)
+	suite.Require().True(allowed)
+}
+
+func (suite *LimiterTestSuite) TestLimit() {
+	router := gin.New()
+	router.GET("/", suite.limiter.Limit(testPolicy), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 5; i++ {
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+		suite.Require().Equal(http.StatusOK, res.Code)
+	}
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+	suite.Require().Equal(http.StatusTooManyRequests, res.Code)
+}
+
+func (suite *LimiterTestSuite) TestDisabled() {
+	cfg := ratelimit.DefaultConfig()
+	cfg.Disabled = true
+	cfg.Policies[testPolicy] = ratelimit.NewPolicy(1, time.Hour, 1, ratelimit.KeyByIP)
+	suite.limiter = ratelimit.NewLimiter(cfg)
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := suite.take(10)
+		suite.Require().True(allowed)
+	}
+}
+
+func (suite *LimiterTestSuite) TestLimit_ForwardedFor() {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		shouldLimit    bool
+	}{
+		{
+			name:        "spoofed header from an untrusted client does not reset the bucket",
+			shouldLimit: true,
+		},
+		{
+			name:           "header set by a trusted proxy identifies the client",
+			trustedProxies: []string{"192.0.2.0/24"},
+			shouldLimit:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+
+			router := gin.New()
+			suite.Require().NoError(router.SetTrustedProxies(tc.trustedProxies))
+			router.GET("/", suite.limiter.Limit(testPolicy), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			// httptest requests all come from 192.0.2.1, so each different X-Forwarded-For value is either
+			// ignored or trusted depending on the proxies configuration
+			var lastCode int
+			for i := 0; i < 6; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i+1))
+
+				res := httptest.NewRecorder()
+				router.ServeHTTP(res, req)
+				lastCode = res.Code
+			}
+
+			if tc.shouldLimit {
+				suite.Require().Equal(http.StatusTooManyRequests, lastCode)
+			} else {
+				suite.Require().Equal(http.StatusOK, lastCode)
+			}
+		})
+	}
+}
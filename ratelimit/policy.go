@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// PolicyCreateLinks represents the policy applied to the routes that create deep links
+	PolicyCreateLinks = "create"
+
+	// PolicyCreateLinksBatch represents the policy applied to the route that creates deep links in batch.
+	// Its limits are expressed in links rather than in requests, since each request can create multiple links
+	PolicyCreateLinksBatch = "create_batch"
+
+	// PolicyReadLinks represents the policy applied to the routes that read deep links data
+	PolicyReadLinks = "read"
+
+	// PolicyAuth represents the policy applied to the wallet login routes
+	PolicyAuth = "auth"
+)
+
+// KeyType represents how the clients are identified while applying a rate limiting policy
+type KeyType string
+
+const (
+	// KeyByClient identifies the clients by their API key, or by their wallet address if they are logged in using
+	// a wallet session token. Unauthenticated clients are identified by their IP
+	KeyByClient KeyType = "client"
+
+	// KeyByIP identifies the clients by their IP
+	KeyByIP KeyType = "ip"
+
+	// KeyByClientAndIP identifies the clients by both their API key (or wallet address) and their IP, so that
+	// each IP using the same API key gets its own limit
+	KeyByClientAndIP KeyType = "client_and_ip"
+)
+
+// Policy contains the configuration of a token bucket rate limiting policy
+type Policy struct {
+	// Requests represents the number of requests that are allowed within each Period
+	Requests int
+
+	// Period represents the time window within which at most Requests requests are allowed
+	Period time.Duration
+
+	// Burst represents the maximum number of requests that can be performed at once
+	Burst int
+
+	// KeyBy represents how the clients are identified
+	KeyBy KeyType
+}
+
+// NewPolicy returns a new Policy instance
+func NewPolicy(requests int, period time.Duration, burst int, keyBy KeyType) *Policy {
+	return &Policy{
+		Requests: requests,
+		Period:   period,
+		Burst:    burst,
+		KeyBy:    keyBy,
+	}
+}
+
+// DefaultPolicies returns the default policies indexed by their name
+func DefaultPolicies() map[string]*Policy {
+	return map[string]*Policy{
+		PolicyCreateLinks:      NewPolicy(60, time.Minute, 10, KeyByClient),
+		PolicyCreateLinksBatch: NewPolicy(600, time.Minute, 500, KeyByClient),
+		PolicyReadLinks:        NewPolicy(600, time.Minute, 100, KeyByClient),
+		PolicyAuth:             NewPolicy(30, time.Minute, 10, KeyByIP),
+	}
+}
+
+// Validate checks whether the policy is valid or not
+func (p *Policy) Validate() error {
+	if p.Requests <= 0 {
+		return fmt.Errorf("invalid requests: must be a positive integer")
+	}
+
+	if p.Period <= 0 {
+		return fmt.Errorf("invalid period: must be a positive duration")
+	}
+
+	if p.Burst <= 0 {
+		return fmt.Errorf("invalid burst: must be a positive integer")
+	}
+
+	switch p.KeyBy {
+	case KeyByClient, KeyByIP, KeyByClientAndIP:
+		return nil
+	default:
+		return fmt.Errorf("invalid key: must be one of %s, %s or %s", KeyByClient, KeyByIP, KeyByClientAndIP)
+	}
+}
+
+// rate returns the number of tokens that are added to the bucket each second
+func (p *Policy) rate() float64 {
+	return float64(p.Requests) / p.Period.Seconds()
+}
+
+// refillTime returns the time needed to completely refill an empty bucket
+func (p *Policy) refillTime() time.Duration {
+	return time.Duration(float64(p.Burst) / p.rate() * float64(time.Second))
+}
+
+// policyJSON represents the JSON representation of a Policy
+type policyJSON struct {
+	Requests int     `json:"requests"`
+	Period   string  `json:"period"`
+	Burst    int     `json:"burst"`
+	KeyBy    KeyType `json:"key"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (p *Policy) UnmarshalJSON(bz []byte) error {
+	var value policyJSON
+	err := json.Unmarshal(bz, &value)
+	if err != nil {
+		return err
+	}
+
+	period, err := time.ParseDuration(value.Period)
+	if err != nil {
+		return fmt.Errorf("invalid period: %s", err)
+	}
+
+	*p = Policy{
+		Requests: value.Requests,
+		Period:   period,
+		Burst:    value.Burst,
+		KeyBy:    value.KeyBy,
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package redaction
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// ModeHash represents the mode in which the addresses are replaced with keyed hashes, so that the values
+	// referring to the same address can still be correlated without revealing it
+	ModeHash = "hash"
+
+	// ModeMask represents the mode in which the addresses are entirely masked
+	ModeMask = "mask"
+
+	// ModePlain represents the mode in which the addresses are kept in plain text.
+	// It should be used only during development
+	ModePlain = "plain"
+)
+
+// minKeyLength represents the minimum length of the key used to hash the addresses
+const minKeyLength = 16
+
+// Config contains the redaction policy applied to the logs and analytics
+type Config struct {
+	// Key represents the secret key used to hash the addresses.
+	// If empty, a random key is used, so the hashes are not stable across restarts nor across multiple instances
+	Key string `yaml:"key"`
+
+	// Mode represents how the addresses are redacted by default (either ModeHash, ModeMask or ModePlain)
+	Mode string `yaml:"mode"`
+
+	// Levels allows to override the mode used for the log messages of specific levels (e.g. "debug": ModePlain)
+	Levels map[string]string `yaml:"levels"`
+}
+
+// DefaultConfig returns the default Config instance, which hashes the addresses using a random key
+func DefaultConfig() *Config {
+	return &Config{
+		Mode: ModeHash,
+	}
+}
+
+// ModeForLevel returns the mode used to redact the log messages having the given level
+func (c *Config) ModeForLevel(level string) string {
+	if mode, ok := c.Levels[level]; ok {
+		return mode
+	}
+	return c.Mode
+}
+
+// Validate checks whether the configuration is valid, returning all the problems found
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Key != "" && len(c.Key) < minKeyLength {
+		errs = append(errs, fmt.Errorf("invalid key: must be at least %d characters long", minKeyLength))
+	}
+
+	if !isValidMode(c.Mode) {
+		errs = append(errs, fmt.Errorf("invalid mode %q: must be either %s, %s or %s", c.Mode, ModeHash, ModeMask, ModePlain))
+	}
+
+	// Sort the levels so that the problems are always reported in the same order
+	levels := make([]string, 0, len(c.Levels))
+	for level := range c.Levels {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+
+	for _, level := range levels {
+		_, err := zerolog.ParseLevel(level)
+		if err != nil || level == "" {
+			errs = append(errs, fmt.Errorf("invalid level %q inside levels", level))
+		}
+
+		mode := c.Levels[level]
+		if !isValidMode(mode) {
+			errs = append(errs, fmt.Errorf("invalid mode %q for level %s: must be either %s, %s or %s", mode, level, ModeHash, ModeMask, ModePlain))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isValidMode tells whether the given mode is a valid one
+func isValidMode(mode string) bool {
+	return mode == ModeHash || mode == ModeMask || mode == ModePlain
+}
@@ -0,0 +1,8 @@
+//nolint:gosec // These are just the names of the env variables
+package redaction
+
+const (
+	EnvRedactionKey    = "REDACTION_KEY"
+	EnvRedactionMode   = "REDACTION_MODE"
+	EnvRedactionLevels = "REDACTION_LEVELS"
+)
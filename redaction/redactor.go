@@ -0,0 +1,89 @@
+package redaction
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Redacted represents the value that replaces the masked values
+	Redacted = "[REDACTED]"
+
+	// hashLength represents the number of hex characters of the hashes that replace the addresses
+	hashLength = 16
+)
+
+var (
+	// addressRegex matches the values that look like bech32 addresses (e.g. desmos1...), made of a human-readable
+	// part, the "1" separator and at least 38 characters from the bech32 charset (20 bytes and the checksum).
+	// Both the lowercase and uppercase forms are matched, while the checksum is not verified so that the addresses
+	// containing typos are redacted as well
+	addressRegex = regexp.MustCompile(`(?i)\b[a-z]{1,83}1[qpzry9x8gf2tvdw0s3jn54khce6mua7l]{38,}\b`)
+
+	// authorizationRegex matches the credentials provided using the Bearer and Basic authorization schemes
+	authorizationRegex = regexp.MustCompile(`(?i)\b(bearer|basic)(\s+)[^\s",;\]]+`)
+)
+
+// Redactor allows to remove the personal data and the credentials from the logs and analytics
+type Redactor struct {
+	cfg *Config
+	key []byte
+}
+
+// NewRedactor returns a new Redactor instance applying the given policy, which must be valid.
+// If the configuration does not contain any key, a random one is generated
+func NewRedactor(cfg *Config) *Redactor {
+	key := []byte(cfg.Key)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, err := rand.Read(key)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return &Redactor{
+		cfg: cfg,
+		key: key,
+	}
+}
+
+// HashAddress returns the keyed hash of the given address, prefixed with its human-readable part
+// (e.g. desmos_3f2a9c1b7d4e6a80). The same address always results in the same hash as long as the key is unchanged
+func (r *Redactor) HashAddress(address string) string {
+	address = strings.ToLower(address)
+
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(address))
+	hash := hex.EncodeToString(mac.Sum(nil))[:hashLength]
+
+	separatorIndex := strings.LastIndex(address, "1")
+	if separatorIndex <= 0 {
+		return hash
+	}
+	return address[:separatorIndex] + "_" + hash
+}
+
+// Redact returns the given text after redacting the addresses it contains using the given mode, and masking
+// the authorization credentials. The credentials are masked even when using ModePlain
+func (r *Redactor) Redact(mode string, text string) string {
+	text = authorizationRegex.ReplaceAllString(text, "${1}${2}"+Redacted)
+
+	switch mode {
+	case ModePlain:
+		return text
+	case ModeMask:
+		return addressRegex.ReplaceAllLiteralString(text, Redacted)
+	default:
+		return addressRegex.ReplaceAllStringFunc(text, r.HashAddress)
+	}
+}
+
+// RedactLevel returns the given text after redacting it using the mode associated with the given log level
+func (r *Redactor) RedactLevel(level string, text string) string {
+	return r.Redact(r.cfg.ModeForLevel(level), text)
+}
@@ -0,0 +1,130 @@
+package redaction_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/redaction"
+)
+
+const (
+	address      = "desmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+	otherAddress = "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+	redactionKey = "0123456789abcdef"
+)
+
+var (
+	// hashedAddressRegex matches the hashes that replace the Desmos addresses
+	hashedAddressRegex = regexp.MustCompile(`^desmos_[0-9a-f]{16}$`)
+)
+
+func TestRedactorTestSuite(t *testing.T) {
+	suite.Run(t, new(RedactorTestSuite))
+}
+
+// RedactorTestSuite tests the redaction of the addresses and the credentials
+type RedactorTestSuite struct {
+	suite.Suite
+
+	redactor *redaction.Redactor
+}
+
+func (suite *RedactorTestSuite) SetupTest() {
+	suite.redactor = redaction.NewRedactor(&redaction.Config{
+		Key:  redactionKey,
+		Mode: redaction.ModeHash,
+		Levels: map[string]string{
+			"debug": redaction.ModePlain,
+			"error": redaction.ModeMask,
+		},
+	})
+}
+
+func (suite *RedactorTestSuite) TestHashAddress() {
+	hash := suite.redactor.HashAddress(address)
+	suite.Require().Regexp(hashedAddressRegex, hash)
+
+	// The hash should be stable and case-insensitive
+	suite.Require().Equal(hash, suite.redactor.HashAddress(address))
+	suite.Require().Equal(hash, suite.redactor.HashAddress(strings.ToUpper(address)))
+
+	// Different addresses and keys should produce different hashes
+	suite.Require().NotEqual(strings.TrimPrefix(hash, "desmos_"), strings.TrimPrefix(suite.redactor.HashAddress(otherAddress), "cosmos_"))
+	otherRedactor := redaction.NewRedactor(&redaction.Config{Key: "fedcba9876543210", Mode: redaction.ModeHash})
+	suite.Require().NotEqual(hash, otherRedactor.HashAddress(address))
+}
+
+func (suite *RedactorTestSuite) TestRedact() {
+	text := "link created for " + address + " and " + strings.ToUpper(otherAddress)
+
+	hashed := suite.redactor.Redact(redaction.ModeHash, text)
+	suite.Require().Equal(
+		"link created for "+suite.redactor.HashAddress(address)+" and "+suite.redactor.HashAddress(otherAddress),
+		hashed,
+	)
+
+	masked := suite.redactor.Redact(redaction.ModeMask, text)
+	suite.Require().Equal("link created for "+redaction.Redacted+" and "+redaction.Redacted, masked)
+
+	suite.Require().Equal(text, suite.redactor.Redact(redaction.ModePlain, text))
+}
+
+func (suite *RedactorTestSuite) TestRedact_JSON() {
+	text := `{"level":"info","address":"` + address + `","path":"/deep-links/` + address + `"}`
+
+	redacted := suite.redactor.Redact(redaction.ModeHash, text)
+	suite.Require().NotContains(redacted, address)
+	suite.Require().Equal(
+		`{"level":"info","address":"`+suite.redactor.HashAddress(address)+`","path":"/deep-links/`+suite.redactor.HashAddress(address)+`"}`,
+		redacted,
+	)
+}
+
+func (suite *RedactorTestSuite) TestRedact_IgnoresOtherValues() {
+	texts := []string{
+		"request handled in 12ms",
+		"desmos1short",
+		"https://dpm.fake.link/3f9a1c0b7d2e4a56",
+	}
+
+	for _, text := range texts {
+		suite.Require().Equal(text, suite.redactor.Redact(redaction.ModeMask, text))
+	}
+}
+
+func (suite *RedactorTestSuite) TestRedact_Credentials() {
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{text: "Authorization: Bearer secret-token", expected: "Authorization: Bearer " + redaction.Redacted},
+		{text: "authorization: basic dXNlcjpwYXNz", expected: "authorization: basic " + redaction.Redacted},
+		{text: `{"authorization":"Bearer abc.def.ghi"}`, expected: `{"authorization":"Bearer ` + redaction.Redacted + `"}`},
+	}
+
+	// The credentials should be masked even when the addresses are kept in plain text
+	for _, mode := range []string{redaction.ModePlain, redaction.ModeMask, redaction.ModeHash} {
+		for _, tc := range testCases {
+			suite.Require().Equal(tc.expected, suite.redactor.Redact(mode, tc.text), "wrong result in %s mode", mode)
+		}
+	}
+}
+
+func (suite *RedactorTestSuite) TestRedactLevel() {
+	text := "address " + address
+
+	suite.Require().Equal(text, suite.redactor.RedactLevel("debug", text))
+	suite.Require().Equal("address "+redaction.Redacted, suite.redactor.RedactLevel("error", text))
+	suite.Require().Equal("address "+suite.redactor.HashAddress(address), suite.redactor.RedactLevel("info", text))
+	suite.Require().Equal("address "+suite.redactor.HashAddress(address), suite.redactor.RedactLevel("", text))
+}
+
+func (suite *RedactorTestSuite) TestRandomKey() {
+	first := redaction.NewRedactor(redaction.DefaultConfig())
+	second := redaction.NewRedactor(redaction.DefaultConfig())
+	suite.Require().Regexp(hashedAddressRegex, first.HashAddress(address))
+	suite.Require().NotEqual(first.HashAddress(address), second.HashAddress(address))
+}
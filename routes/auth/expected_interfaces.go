@@ -0,0 +1,10 @@
+package auth
+
+import (
+	"time"
+)
+
+type SessionsManager interface {
+	IssueNonce(address string) (nonce string, expiresAt time.Time, err error)
+	Login(address string, nonce string, pubKey []byte, signature []byte) (token string, expiresAt time.Time, err error)
+}
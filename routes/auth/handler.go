@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"net/http"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/desmos-labs/dpm-apis/authentication"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+type Handler struct {
+	sessions SessionsManager
+}
+
+func NewHandler(sessions SessionsManager) *Handler {
+	return &Handler{
+		sessions: sessions,
+	}
+}
+
+// HandleNonceRequest handles the given NonceRequest returning a new nonce or an error
+func (h *Handler) HandleNonceRequest(req *NonceRequest) (*NonceResponse, error) {
+	_, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidAddress, "invalid address").WithParam("address")
+	}
+
+	nonce, expiresAt, err := h.sessions.IssueNonce(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNonceResponse(nonce, authentication.GetLoginMessage(nonce), expiresAt), nil
+}
+
+// HandleLoginRequest handles the given LoginRequest returning a new session token or an error
+func (h *Handler) HandleLoginRequest(req *LoginRequest) (*LoginResponse, error) {
+	_, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidAddress, "invalid address").WithParam("address")
+	}
+
+	token, expiresAt, err := h.sessions.Login(req.Address, req.Nonce, req.PubKey, req.Signature)
+	if err != nil {
+		return nil, utils.WrapErr(http.StatusUnauthorized, utils.ErrCodeInvalidSignature, err.Error())
+	}
+
+	return NewLoginResponse(token, expiresAt), nil
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/desmos-labs/dpm-apis/ratelimit"
+	"github.com/desmos-labs/dpm-apis/routes"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+func RegisterWithContext(ctx routes.Context) {
+	Register(ctx.Router, NewHandler(ctx.Authenticator.Sessions()), ctx.RateLimiter)
+}
+
+// Register registers all the routes that allow users to authenticate using their wallet.
+// All the routes are rate limited using the given limiter
+func Register(router *gin.Engine, handler *Handler, limiter *ratelimit.Limiter) {
+	router.Group("/auth", limiter.Limit(ratelimit.PolicyAuth)).
+		POST("/nonce", func(c *gin.Context) {
+			// Build the request
+			var req NonceRequest
+			err := c.ShouldBindJSON(&req)
+			if err != nil {
+				utils.HandleError(c, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidRequestBody, "invalid request body"))
+				return
+			}
+
+			// Handle the request
+			res, err := handler.HandleNonceRequest(&req)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+
+			// Return the response
+			c.JSON(http.StatusOK, res)
+		}).
+		POST("/login", func(c *gin.Context) {
+			// Build the request
+			var req LoginRequest
+			err := c.ShouldBindJSON(&req)
+			if err != nil {
+				utils.HandleError(c, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidRequestBody, "invalid request body"))
+				return
+			}
+
+			// Handle the request
+			res, err := handler.HandleLoginRequest(&req)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+
+			// Return the response
+			c.JSON(http.StatusOK, res)
+		})
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"time"
+)
+
+// NonceRequest represents the request sent to get a new nonce to be signed in order to log in
+type NonceRequest struct {
+	// Address represents the address of the user that wants to log in
+	Address string `json:"address"`
+}
+
+// NonceResponse represents the response returned when a new nonce is issued
+type NonceResponse struct {
+	// Nonce represents the nonce that has been issued
+	Nonce string `json:"nonce"`
+
+	// Message represents the message that the user has to sign following the ADR-036 specification
+	Message string `json:"message"`
+
+	// ExpiresAt represents the time after which the nonce cannot be used anymore
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewNonceResponse(nonce string, message string, expiresAt time.Time) *NonceResponse {
+	return &NonceResponse{
+		Nonce:     nonce,
+		Message:   message,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// LoginRequest represents the request sent to log in using a signed nonce
+type LoginRequest struct {
+	// Address represents the address of the user that wants to log in
+	Address string `json:"address"`
+
+	// Nonce represents the nonce that has been issued to the user
+	Nonce string `json:"nonce"`
+
+	// PubKey represents the base64-encoded compressed secp256k1 public key of the user
+	PubKey []byte `json:"pub_key"`
+
+	// Signature represents the base64-encoded signature of the nonce message
+	Signature []byte `json:"signature"`
+}
+
+// LoginResponse represents the response returned when a user logs in
+type LoginResponse struct {
+	// Token represents the session token that must be used to authenticate the following requests
+	Token string `json:"token"`
+
+	// ExpiresAt represents the time after which the session token cannot be used anymore
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewLoginResponse(token string, expiresAt time.Time) *LoginResponse {
+	return &LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+}
@@ -1,13 +1,28 @@
 package routes
 
 import (
+	"sync/atomic"
+
 	"github.com/gin-gonic/gin"
 
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/authentication"
+	"github.com/desmos-labs/dpm-apis/cache"
 	"github.com/desmos-labs/dpm-apis/caerus"
+	"github.com/desmos-labs/dpm-apis/database"
+	"github.com/desmos-labs/dpm-apis/ratelimit"
 )
 
 // Context contains all the data that can be useful while registering routes
 type Context struct {
-	Router *gin.Engine
-	Caerus *caerus.Client
+	Router          *gin.Engine
+	Caerus          *caerus.Client
+	LinkConfigCache *cache.LinkConfigCache
+	Database        database.Database
+	Authenticator   *authentication.Authenticator
+	RateLimiter     *ratelimit.Limiter
+	Analytics       *analytics.Tracker
+
+	// Draining tells whether the server is shutting down and should not receive new traffic
+	Draining *atomic.Bool
 }
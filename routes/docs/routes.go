@@ -0,0 +1,68 @@
+package docs
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/desmos-labs/dpm-apis/routes"
+)
+
+const (
+	SpecPath     = "/openapi.json"
+	UIPath       = "/docs"
+	UIScriptPath = "/docs/swagger-ui-bundle.js"
+	UIStylePath  = "/docs/swagger-ui.css"
+
+	// uiAssetsMaxAge represents how long the clients can cache the embedded UI assets
+	uiAssetsMaxAge = 24 * time.Hour
+)
+
+var (
+	// Spec contains the OpenAPI 3 specification of all the routes exposed by the APIs
+	//go:embed openapi.json
+	Spec []byte
+
+	// uiPage, uiScript and uiStyle contain the page that renders the specification and the Swagger UI assets it uses,
+	// which are embedded so that the page does not load any script from third-party servers
+	//go:embed ui/index.html
+	uiPage []byte
+
+	//go:embed ui/swagger-ui-bundle.js
+	uiScript []byte
+
+	//go:embed ui/swagger-ui.css
+	uiStyle []byte
+)
+
+func RegisterWithContext(ctx routes.Context) {
+	Register(ctx.Router)
+}
+
+// Register registers the routes that serve the OpenAPI specification of the APIs and the page that renders it, along
+// with the assets used by such page.
+// These routes are not authenticated so that the documentation can be browsed without any credentials
+func Register(router *gin.Engine) {
+	router.
+		GET(SpecPath, func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json; charset=utf-8", Spec)
+		}).
+		GET(UIPath, func(c *gin.Context) {
+			c.Data(http.StatusOK, "text/html; charset=utf-8", uiPage)
+		}).
+		GET(UIScriptPath, func(c *gin.Context) {
+			serveUIAsset(c, "text/javascript; charset=utf-8", uiScript)
+		}).
+		GET(UIStylePath, func(c *gin.Context) {
+			serveUIAsset(c, "text/css; charset=utf-8", uiStyle)
+		})
+}
+
+// serveUIAsset serves the given embedded UI asset, allowing the clients to cache it
+func serveUIAsset(c *gin.Context, contentType string, asset []byte) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(uiAssetsMaxAge.Seconds())))
+	c.Data(http.StatusOK, contentType, asset)
+}
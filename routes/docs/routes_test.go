@@ -0,0 +1,186 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/cache"
+	"github.com/desmos-labs/dpm-apis/database/memory"
+	"github.com/desmos-labs/dpm-apis/internal/testutil"
+	"github.com/desmos-labs/dpm-apis/routes/auth"
+	"github.com/desmos-labs/dpm-apis/routes/docs"
+	"github.com/desmos-labs/dpm-apis/routes/health"
+	"github.com/desmos-labs/dpm-apis/routes/links"
+)
+
+var (
+	// pathParamRegex matches the Gin path params (e.g. :address)
+	pathParamRegex = regexp.MustCompile(`:([a-zA-Z_]+)`)
+
+	// refRegex matches the local references contained inside the specification
+	refRegex = regexp.MustCompile(`"\$ref":\s*"#/([^"]+)"`)
+
+	// externalResourceRegex matches the scripts and stylesheets loaded from other servers
+	externalResourceRegex = regexp.MustCompile(`(src|href)="(https?:)?//`)
+)
+
+func TestDocsTestSuite(t *testing.T) {
+	suite.Run(t, new(DocsTestSuite))
+}
+
+// DocsTestSuite makes sure that the OpenAPI specification is kept in sync with the routes registered by the APIs
+type DocsTestSuite struct {
+	suite.Suite
+
+	spec   *openAPISpec
+	router *gin.Engine
+}
+
+// openAPISpec contains the parts of an OpenAPI specification that are checked by the tests
+type openAPISpec struct {
+	OpenAPI string                                  `json:"openapi"`
+	Paths   map[string]map[string]*openAPIOperation `json:"paths"`
+}
+
+// openAPIOperation contains the parts of an OpenAPI operation that are checked by the tests
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Responses   map[string]json.RawMessage `json:"responses"`
+}
+
+func (suite *DocsTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+
+	var spec openAPISpec
+	suite.Require().NoError(json.Unmarshal(docs.Spec, &spec))
+	suite.spec = &spec
+
+	authenticator, err := testutil.NewAuthenticator()
+	suite.Require().NoError(err)
+
+	limiter := testutil.NewDisabledLimiter()
+	linksHandler := links.NewHandler(
+		links.DefaultConfig(),
+		nil,
+		cache.NewLinkConfigCache(cache.DefaultLinkConfigCacheConfig()),
+		memory.NewDatabase(),
+		testutil.TrackerStub{},
+	)
+
+	suite.router = gin.New()
+	health.Register(suite.router, health.NewHandler(nil, &atomic.Bool{}))
+	auth.Register(suite.router, auth.NewHandler(authenticator.Sessions()), limiter)
+	links.Register(suite.router, linksHandler, authenticator, limiter)
+	docs.Register(suite.router)
+}
+
+// documentedRoutes returns the routes that should be documented, in the "METHOD /path" form
+func (suite *DocsTestSuite) documentedRoutes() []string {
+	var result []string
+	for _, route := range suite.router.Routes() {
+		switch route.Path {
+		case docs.SpecPath, docs.UIPath, docs.UIScriptPath, docs.UIStylePath:
+			continue
+		}
+
+		path := pathParamRegex.ReplaceAllString(route.Path, "{$1}")
+		result = append(result, route.Method+" "+path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// specRoutes returns the routes described by the specification, in the "METHOD /path" form
+func (suite *DocsTestSuite) specRoutes() []string {
+	var result []string
+	for path, operations := range suite.spec.Paths {
+		for method := range operations {
+			result = append(result, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+func (suite *DocsTestSuite) TestSpecVersion() {
+	suite.Require().True(strings.HasPrefix(suite.spec.OpenAPI, "3."))
+}
+
+func (suite *DocsTestSuite) TestSpecMatchesRoutes() {
+	suite.Require().Equal(suite.documentedRoutes(), suite.specRoutes(), "the OpenAPI specification is out of sync with the registered routes")
+}
+
+func (suite *DocsTestSuite) TestOperationsAreComplete() {
+	operationIDs := map[string]bool{}
+	for path, operations := range suite.spec.Paths {
+		for method, operation := range operations {
+			suite.Require().NotEmpty(operation.OperationID, "missing operationId for %s %s", method, path)
+			suite.Require().False(operationIDs[operation.OperationID], "duplicated operationId %s", operation.OperationID)
+			suite.Require().NotEmpty(operation.Responses, "missing responses for %s %s", method, path)
+			operationIDs[operation.OperationID] = true
+		}
+	}
+}
+
+func (suite *DocsTestSuite) TestReferencesAreResolved() {
+	var spec map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(docs.Spec, &spec))
+
+	for _, match := range refRegex.FindAllStringSubmatch(string(docs.Spec), -1) {
+		var node interface{} = spec
+		for _, part := range strings.Split(match[1], "/") {
+			object, ok := node.(map[string]interface{})
+			suite.Require().True(ok, "unresolved reference #/%s", match[1])
+			node, ok = object[part]
+			suite.Require().True(ok, "unresolved reference #/%s", match[1])
+		}
+	}
+}
+
+func (suite *DocsTestSuite) TestServeSpec() {
+	res := httptest.NewRecorder()
+	suite.router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, docs.SpecPath, nil))
+	suite.Require().Equal(http.StatusOK, res.Code)
+	suite.Require().Contains(res.Header().Get("Content-Type"), "application/json")
+	suite.Require().JSONEq(string(docs.Spec), res.Body.String())
+}
+
+func (suite *DocsTestSuite) TestServeUI() {
+	res := httptest.NewRecorder()
+	suite.router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, docs.UIPath, nil))
+	suite.Require().Equal(http.StatusOK, res.Code)
+	suite.Require().Contains(res.Header().Get("Content-Type"), "text/html")
+	suite.Require().Contains(res.Body.String(), docs.SpecPath)
+
+	// The page must only load the embedded assets
+	suite.Require().Contains(res.Body.String(), docs.UIScriptPath)
+	suite.Require().Contains(res.Body.String(), docs.UIStylePath)
+	suite.Require().False(externalResourceRegex.MatchString(res.Body.String()), "the page loads external resources")
+}
+
+func (suite *DocsTestSuite) TestServeUIAssets() {
+	assets := map[string]string{
+		docs.UIScriptPath: "text/javascript",
+		docs.UIStylePath:  "text/css",
+	}
+
+	for path, contentType := range assets {
+		res := httptest.NewRecorder()
+		suite.router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		suite.Require().Equal(http.StatusOK, res.Code)
+		suite.Require().Contains(res.Header().Get("Content-Type"), contentType)
+		suite.Require().NotEmpty(res.Header().Get("Cache-Control"))
+		suite.Require().NotZero(res.Body.Len())
+	}
+}
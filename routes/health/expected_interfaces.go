@@ -0,0 +1,10 @@
+package health
+
+import (
+	"context"
+)
+
+type CaerusClient interface {
+	CheckHealth(ctx context.Context) error
+	CircuitBreakerState() string
+}
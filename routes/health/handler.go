@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// checkTimeout represents the maximum time allowed for each readiness check
+	checkTimeout = 2 * time.Second
+)
+
+type Handler struct {
+	caerus   CaerusClient
+	draining *atomic.Bool
+}
+
+func NewHandler(caerusClient CaerusClient, draining *atomic.Bool) *Handler {
+	return &Handler{
+		caerus:   caerusClient,
+		draining: draining,
+	}
+}
+
+// HandleLivenessRequest returns the liveness status of the APIs, which are alive as long as they can handle requests
+func (h *Handler) HandleLivenessRequest() *HealthResponse {
+	return NewHealthResponse(StatusOK, nil)
+}
+
+// HandleReadinessRequest returns the readiness status of the APIs, which are ready to receive traffic only if they are
+// not shutting down and all the services they depend on are available.
+// It returns true if the APIs are ready, false otherwise
+func (h *Handler) HandleReadinessRequest(ctx context.Context) (*HealthResponse, bool) {
+	if h.draining.Load() {
+		return NewHealthResponse(StatusDraining, nil), false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	checks := map[string]*CheckResult{
+		"caerus": NewCheckResult("caerus", h.caerus.CheckHealth(ctx)).WithDetail("circuit_breaker", h.caerus.CircuitBreakerState()),
+	}
+
+	for _, check := range checks {
+		if !check.IsOK() {
+			return NewHealthResponse(StatusUnavailable, checks), false
+		}
+	}
+
+	return NewHealthResponse(StatusOK, checks), true
+}
@@ -0,0 +1,67 @@
+package health_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/routes/health"
+)
+
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
+
+// HandlerTestSuite tests the readiness checks
+type HandlerTestSuite struct {
+	suite.Suite
+}
+
+// caerusStub is a health.CaerusClient that returns a fixed health check error
+type caerusStub struct {
+	err error
+}
+
+func (c caerusStub) CheckHealth(context.Context) error {
+	return c.err
+}
+
+func (c caerusStub) CircuitBreakerState() string {
+	return "closed"
+}
+
+func (suite *HandlerTestSuite) TestReadiness_Available() {
+	handler := health.NewHandler(caerusStub{}, &atomic.Bool{})
+
+	res, ready := handler.HandleReadinessRequest(context.Background())
+	suite.Require().True(ready)
+	suite.Require().Equal(health.StatusOK, res.Status)
+	suite.Require().Equal(health.StatusOK, res.Checks["caerus"].Status)
+	suite.Require().Empty(res.Checks["caerus"].Error)
+}
+
+func (suite *HandlerTestSuite) TestReadiness_Unavailable() {
+	err := fmt.Errorf("dial tcp 10.0.0.1:443: connection refused")
+	handler := health.NewHandler(caerusStub{err: err}, &atomic.Bool{})
+
+	res, ready := handler.HandleReadinessRequest(context.Background())
+	suite.Require().False(ready)
+	suite.Require().Equal(health.StatusUnavailable, res.Status)
+	suite.Require().Equal(health.StatusUnavailable, res.Checks["caerus"].Status)
+	suite.Require().Equal(health.ErrCheckFailed, res.Checks["caerus"].Error)
+	suite.Require().NotContains(res.Checks["caerus"].Error, "10.0.0.1")
+}
+
+func (suite *HandlerTestSuite) TestReadiness_Draining() {
+	draining := &atomic.Bool{}
+	draining.Store(true)
+	handler := health.NewHandler(caerusStub{}, draining)
+
+	res, ready := handler.HandleReadinessRequest(context.Background())
+	suite.Require().False(ready)
+	suite.Require().Equal(health.StatusDraining, res.Status)
+	suite.Require().Empty(res.Checks)
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/desmos-labs/dpm-apis/routes"
+)
+
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+func RegisterWithContext(ctx routes.Context) {
+	Register(ctx.Router, NewHandler(ctx.Caerus, ctx.Draining))
+}
+
+// Register registers the liveness and readiness routes.
+// These routes are not authenticated so that they can be used by the orchestrator probes
+func Register(router *gin.Engine, handler *Handler) {
+	router.
+		GET(LivenessPath, func(c *gin.Context) {
+			c.JSON(http.StatusOK, handler.HandleLivenessRequest())
+		}).
+		GET(ReadinessPath, func(c *gin.Context) {
+			res, ready := handler.HandleReadinessRequest(c.Request.Context())
+			if !ready {
+				c.JSON(http.StatusServiceUnavailable, res)
+				return
+			}
+
+			c.JSON(http.StatusOK, res)
+		})
+}
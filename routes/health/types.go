@@ -0,0 +1,70 @@
+package health
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+const (
+	// ErrCheckFailed is the reason exposed when a check fails, so that the internal errors are never returned to
+	// unauthenticated clients
+	ErrCheckFailed = "check failed"
+)
+
+// CheckResult contains the result of a single readiness check
+type CheckResult struct {
+	// Status represents the status of the checked component (either StatusOK or StatusUnavailable)
+	Status string `json:"status"`
+
+	// Error contains a generic reason why the component is not available, if any.
+	// The actual error is only logged
+	Error string `json:"error,omitempty"`
+
+	// Details contains additional information about the status of the component
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// NewCheckResult returns a new CheckResult instance based on the error returned when checking the given component.
+// The error is logged, and only a generic reason is exposed inside the result
+func NewCheckResult(component string, err error) *CheckResult {
+	if err != nil {
+		log.Error().Err(err).Str("component", component).Msg("readiness check failed")
+		return &CheckResult{Status: StatusUnavailable, Error: ErrCheckFailed}
+	}
+	return &CheckResult{Status: StatusOK}
+}
+
+// WithDetail adds the given detail to the result
+func (r *CheckResult) WithDetail(key string, value string) *CheckResult {
+	if r.Details == nil {
+		r.Details = make(map[string]string)
+	}
+	r.Details[key] = value
+	return r
+}
+
+// IsOK tells whether the checked component is available or not
+func (r *CheckResult) IsOK() bool {
+	return r.Status == StatusOK
+}
+
+// HealthResponse represents the response returned by the health endpoints
+type HealthResponse struct {
+	// Status represents the overall status of the APIs
+	Status string `json:"status"`
+
+	// Checks contains the results of the single checks, indexed by the name of the checked component
+	Checks map[string]*CheckResult `json:"checks,omitempty"`
+}
+
+func NewHealthResponse(status string, checks map[string]*CheckResult) *HealthResponse {
+	return &HealthResponse{
+		Status: status,
+		Checks: checks,
+	}
+}
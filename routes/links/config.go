@@ -0,0 +1,81 @@
+package links
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	caerusutils "github.com/desmos-labs/caerus/utils"
+
+	"github.com/desmos-labs/dpm-apis/qrcode"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+const (
+	EnvBatchConcurrency = "LINKS_BATCH_CONCURRENCY"
+	EnvBatchMaxSize     = "LINKS_BATCH_MAX_SIZE"
+	EnvQRLogoPath       = "LINKS_QR_LOGO_PATH"
+)
+
+// Config contains the configuration used by the Handler
+type Config struct {
+	// BatchConcurrency represents the maximum number of links that are created concurrently while handling a batch
+	BatchConcurrency int
+
+	// BatchMaxSize represents the maximum number of links that can be requested within a single batch
+	BatchMaxSize int
+
+	// QRLogo represents the optional logo that can be drawn at the center of the QR codes
+	QRLogo image.Image
+
+	// CreationTimeout represents the maximum duration of a link creation that is shared by equal requests.
+	// It should match the timeout of the Caerus calls
+	CreationTimeout time.Duration
+}
+
+// DefaultConfig returns the default Config instance
+func DefaultConfig() *Config {
+	return &Config{
+		BatchConcurrency: 10,
+		BatchMaxSize:     500,
+		CreationTimeout:  10 * time.Second,
+	}
+}
+
+// ReadConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// It returns an error if any value is not valid or the QR code logo cannot be loaded
+func ReadConfigFromEnvVariables() (*Config, error) {
+	cfg := DefaultConfig()
+
+	var err error
+	cfg.BatchConcurrency, err = utils.ParsePositiveIntEnvOr(EnvBatchConcurrency, cfg.BatchConcurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.BatchMaxSize, err = utils.ParsePositiveIntEnvOr(EnvBatchMaxSize, cfg.BatchMaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	qrLogoPath := caerusutils.GetEnvOr(EnvQRLogoPath, "")
+	if qrLogoPath != "" {
+		logo, err := qrcode.LoadLogo(qrLogoPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", EnvQRLogoPath, err)
+		}
+		cfg.QRLogo = logo
+	}
+
+	return cfg, nil
+}
+
+// NewConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// It panics if any value is not valid
+func NewConfigFromEnvVariables() *Config {
+	cfg, err := ReadConfigFromEnvVariables()
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
@@ -1,14 +1,53 @@
 package links
 
 import (
+	"context"
+
 	caeruslinks "github.com/desmos-labs/caerus/routes/links"
 	caerustypes "github.com/desmos-labs/caerus/types"
+
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/types"
 )
 
 type CaerusClient interface {
-	CreateAddressLink(request *caeruslinks.CreateAddressLinkRequest) (*caeruslinks.CreateLinkResponse, error)
-	CreateViewProfileLink(request *caeruslinks.CreateViewProfileLinkRequest) (*caeruslinks.CreateLinkResponse, error)
-	CreateSendLink(request *caeruslinks.CreateSendLinkRequest) (*caeruslinks.CreateLinkResponse, error)
-	CreateLink(config *caerustypes.LinkConfig) (*caeruslinks.CreateLinkResponse, error)
-	GetLinkConfig(url string) (*caerustypes.LinkConfig, error)
+	CreateAddressLink(ctx context.Context, request *caeruslinks.CreateAddressLinkRequest) (*caeruslinks.CreateLinkResponse, error)
+	CreateViewProfileLink(ctx context.Context, request *caeruslinks.CreateViewProfileLinkRequest) (*caeruslinks.CreateLinkResponse, error)
+	CreateSendLink(ctx context.Context, request *caeruslinks.CreateSendLinkRequest) (*caeruslinks.CreateLinkResponse, error)
+	CreateLink(ctx context.Context, config *caerustypes.LinkConfig) (*caeruslinks.CreateLinkResponse, error)
+	GetLinkConfig(ctx context.Context, url string) (*caerustypes.LinkConfig, error)
+}
+
+// LinkConfigCache represents the cache used to avoid fetching the same link configuration multiple times
+type LinkConfigCache interface {
+	// Get returns the cached configuration of the link having the given URL.
+	// If found is true but the configuration is nil, it means that the link does not exist
+	Get(url string) (config *caerustypes.LinkConfig, found bool)
+
+	// Set caches the given configuration for the link having the given URL.
+	// A nil configuration means that the link does not exist
+	Set(url string, config *caerustypes.LinkConfig)
+}
+
+// Database represents the storage used to keep track of the links that have been created
+type Database interface {
+	// GetLinkURLByKey returns the URL of the link that has been created for the request having the given
+	// idempotency key, or an empty string if no link has been created for such request
+	GetLinkURLByKey(key string) (string, error)
+
+	// SaveLinkURL stores the URL of the link that has been created for the request having the given idempotency key
+	SaveLinkURL(key string, url string) error
+
+	// SaveCreatedLink stores the given link
+	SaveCreatedLink(link *types.CreatedLink) error
+
+	// GetAddressLinks returns the links associated with the given address, from the most recent to the oldest one,
+	// along with the total number of links associated with such address
+	GetAddressLinks(address string, pagination *types.Pagination) ([]*types.CreatedLink, uint64, error)
+}
+
+// Tracker represents the tracker used to record the usage analytics
+type Tracker interface {
+	// Track records the given event. It must never block
+	Track(event *analytics.Event)
 }
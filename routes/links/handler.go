@@ -1,73 +1,351 @@
 package links
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	caeruslinks "github.com/desmos-labs/caerus/routes/links"
+	caerustypes "github.com/desmos-labs/caerus/types"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/singleflight"
 
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/qrcode"
 	"github.com/desmos-labs/dpm-apis/utils"
 )
 
+var (
+	tracer = otel.Tracer("github.com/desmos-labs/dpm-apis/routes/links")
+)
+
 type Handler struct {
-	caerus CaerusClient
+	cfg         *Config
+	caerus      CaerusClient
+	configCache LinkConfigCache
+	db          Database
+	tracker     Tracker
+
+	// creations makes sure that equal requests handled at the same time share a single link creation
+	creations singleflight.Group
 }
 
-func NewHandler(caerusClient CaerusClient) *Handler {
+func NewHandler(cfg *Config, caerusClient CaerusClient, configCache LinkConfigCache, db Database, tracker Tracker) *Handler {
 	return &Handler{
-		caerus: caerusClient,
+		cfg:         cfg,
+		caerus:      caerusClient,
+		configCache: configCache,
+		db:          db,
+		tracker:     tracker,
 	}
 }
 
-// HandleCreateAddressLinkRequest handles the given CreateAddressLinkRequest returning the link address or an error
-func (h *Handler) HandleCreateAddressLinkRequest(req *CreateAddressLinkRequest) (*CreateLinkResponse, error) {
-	res, err := h.caerus.CreateAddressLink(&caeruslinks.CreateAddressLinkRequest{
-		Address: req.Address,
-		Chain:   req.ChainType,
-	})
+// linkRequest represents a generic link creation request
+type linkRequest interface {
+	// getLinkData returns the data that identifies the request
+	getLinkData() (*linkData, error)
+}
+
+// createLink creates a new link using the given function, unless a link has already been created for an equal
+// request. In this case the existing link is returned instead, unless the options force the creation of a new one.
+// Every newly created link is stored inside the database, and the outcome of each request is tracked.
+// Invalid requests are rejected before reaching this method, so they are never tracked
+func (h *Handler) createLink(
+	ctx context.Context, req linkRequest, opts CreationOptions, create func(ctx context.Context) (*caeruslinks.CreateLinkResponse, error),
+) (*CreateLinkResponse, error) {
+	data, err := req.getLinkData()
 	if err != nil {
 		return nil, err
 	}
 
-	return NewCreateLinkResponse(res.Url), nil
+	res, err := h.createLinkFromData(ctx, data, opts, create)
+	h.tracker.Track(analytics.NewEvent(analytics.EventCreateLink, data.Action, data.ChainType, opts.CreatorKey, err))
+	return res, err
 }
 
-// HandleCreateViewProfileLinkRequest handles the given CreateViewProfileLinkRequest returning the link address or an error
-func (h *Handler) HandleCreateViewProfileLinkRequest(req *CreateViewProfileLinkRequest) (*CreateLinkResponse, error) {
-	res, err := h.caerus.CreateViewProfileLink(&caeruslinks.CreateViewProfileLinkRequest{
-		Address: req.Address,
-		Chain:   req.ChainType,
+// createLinkFromData creates a new link identified by the given data, as described by createLink
+func (h *Handler) createLinkFromData(
+	ctx context.Context, data *linkData, opts CreationOptions, create func(ctx context.Context) (*caeruslinks.CreateLinkResponse, error),
+) (*CreateLinkResponse, error) {
+	ctx, span := tracer.Start(ctx, "createLink")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("link.action", data.Action),
+		attribute.String("link.chain_type", data.ChainType),
+		attribute.Bool("link.force_new", opts.ForceNew),
+	)
+
+	key, err := data.IdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ForceNew {
+		return h.createAndSaveLink(ctx, key, data, opts, create)
+	}
+
+	// Equal requests handled at the same time wait for the first one to complete and share its link, so that they
+	// do not create multiple links. Since the link is shared, it is created within a context that is detached from
+	// the first request: otherwise all the waiting requests would fail if the first one was canceled
+	res, err, shared := h.creations.Do(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(utils.DetachedContext(ctx), h.cfg.CreationTimeout)
+		defer cancel()
+
+		linkURL, err := h.db.GetLinkURLByKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if linkURL != "" {
+			span.SetAttributes(attribute.Bool("link.reused", true))
+			return NewCreateLinkResponse(linkURL), nil
+		}
+
+		return h.createAndSaveLink(ctx, key, data, opts, create)
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(attribute.Bool("link.shared", shared))
+	return res.(*CreateLinkResponse), nil
+}
+
+// createAndSaveLink creates a new link using the given function, and stores it inside the database
+// associating it with the given idempotency key.
+// Since the link exists as soon as it is created, it is returned even if it cannot be stored: returning an error
+// would make the caller retry, creating another link and leaving this one orphaned
+func (h *Handler) createAndSaveLink(
+	ctx context.Context, key string, data *linkData, opts CreationOptions, create func(ctx context.Context) (*caeruslinks.CreateLinkResponse, error),
+) (*CreateLinkResponse, error) {
+	res, err := create(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.db.SaveCreatedLink(data.CreatedLink(res.Url, opts.CreatorKey, time.Now()))
+	if err != nil {
+		log.Error().Err(err).Str("link", res.Url).Msg("error while saving created link")
+	}
+
+	err = h.db.SaveLinkURL(key, res.Url)
+	if err != nil {
+		log.Error().Err(err).Str("link", res.Url).Msg("error while saving link idempotency key")
+	}
+
 	return NewCreateLinkResponse(res.Url), nil
 }
 
+// HandleCreateAddressLinkRequest handles the given CreateAddressLinkRequest returning the link address or an error
+func (h *Handler) HandleCreateAddressLinkRequest(ctx context.Context, req *CreateAddressLinkRequest) (*CreateLinkResponse, error) {
+	return h.createLink(ctx, req, req.CreationOptions, func(ctx context.Context) (*caeruslinks.CreateLinkResponse, error) {
+		return h.caerus.CreateAddressLink(ctx, &caeruslinks.CreateAddressLinkRequest{
+			Address: req.Address,
+			Chain:   req.ChainType,
+		})
+	})
+}
+
+// HandleCreateViewProfileLinkRequest handles the given CreateViewProfileLinkRequest returning the link address or an error
+func (h *Handler) HandleCreateViewProfileLinkRequest(ctx context.Context, req *CreateViewProfileLinkRequest) (*CreateLinkResponse, error) {
+	return h.createLink(ctx, req, req.CreationOptions, func(ctx context.Context) (*caeruslinks.CreateLinkResponse, error) {
+		return h.caerus.CreateViewProfileLink(ctx, &caeruslinks.CreateViewProfileLinkRequest{
+			Address: req.Address,
+			Chain:   req.ChainType,
+		})
+	})
+}
+
 // HandleCreateSendLinkRequest handles the given CreateSendLinkRequest returning the link address or an error
-func (h *Handler) HandleCreateSendLinkRequest(req *CreateSendLinkRequest) (*CreateLinkResponse, error) {
-	res, err := h.caerus.CreateSendLink(&caeruslinks.CreateSendLinkRequest{
-		Address: req.Address,
-		Amount:  req.Amount,
-		Chain:   req.ChainType,
+func (h *Handler) HandleCreateSendLinkRequest(ctx context.Context, req *CreateSendLinkRequest) (*CreateLinkResponse, error) {
+	return h.createLink(ctx, req, req.CreationOptions, func(ctx context.Context) (*caeruslinks.CreateLinkResponse, error) {
+		return h.caerus.CreateSendLink(ctx, &caeruslinks.CreateSendLinkRequest{
+			Address: req.Address,
+			Amount:  req.Amount,
+			Chain:   req.ChainType,
+		})
 	})
+}
+
+// HandleCreateLinkRequest handles the given CreateLinkRequest returning the link address or an error
+func (h *Handler) HandleCreateLinkRequest(ctx context.Context, req *CreateLinkRequest) (*CreateLinkResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidLinkConfig, err.Error())
+	}
+
+	config, err := req.LinkConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return NewCreateLinkResponse(res.Url), nil
+	return h.createLink(ctx, req, req.CreationOptions, func(ctx context.Context) (*caeruslinks.CreateLinkResponse, error) {
+		return h.caerus.CreateLink(ctx, config)
+	})
 }
 
-// HandleGetLinkConfigRequest handles the given GetLinkConfigRequest returning the link config or an error
-func (h *Handler) HandleGetLinkConfigRequest(url string) (*GetLinkConfigResponse, error) {
-	res, err := h.caerus.GetLinkConfig(url)
+// HandleCreateLinksBatchRequest handles the given CreateLinksBatchRequest returning the result of each request.
+// The links are created concurrently, and the failure of a single request does not fail the whole batch
+func (h *Handler) HandleCreateLinksBatchRequest(ctx context.Context, req *CreateLinksBatchRequest) (*CreateLinksBatchResponse, error) {
+	if len(req.Requests) == 0 {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidBatch, "empty batch").WithParam("requests")
+	}
+
+	if len(req.Requests) > h.cfg.BatchMaxSize {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidBatch, fmt.Sprintf("batch too big: max %d requests allowed", h.cfg.BatchMaxSize)).WithParam("requests")
+	}
+
+	results := make([]*BatchLinkResult, len(req.Requests))
+	semaphore := make(chan struct{}, h.cfg.BatchConcurrency)
+
+	var wg sync.WaitGroup
+	for i, request := range req.Requests {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(index int, request *BatchLinkRequest) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			res, err := h.handleBatchLinkRequest(ctx, request, req.CreatorKey)
+			if err != nil {
+				results[index] = NewBatchLinkFailure(err)
+
+				// The details of the server errors are not returned to the user, so log them here
+				if results[index].Status >= http.StatusInternalServerError {
+					log.Error().Err(err).Int("index", index).Msg("error while creating batch link")
+				}
+				return
+			}
+			results[index] = NewBatchLinkSuccess(res.DeepLink)
+		}(i, request)
+	}
+	wg.Wait()
+
+	return &CreateLinksBatchResponse{Results: results}, nil
+}
+
+// handleBatchLinkRequest handles a single BatchLinkRequest by forwarding it to the proper handling method
+func (h *Handler) handleBatchLinkRequest(ctx context.Context, req *BatchLinkRequest, creatorKey string) (*CreateLinkResponse, error) {
+	if req == nil {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidRequestBody, "invalid request")
+	}
+
+	opts := NewCreationOptions(req.ForceNew, creatorKey)
+
+	if req.Type == LinkTypeCustom {
+		if req.Config == nil {
+			return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeMissingParam, "missing config").WithParam("config")
+		}
+		req.Config.CreationOptions = opts
+		return h.HandleCreateLinkRequest(ctx, req.Config)
+	}
+
+	address, err := parseAddressValue(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	chainType, err := parseChainTypeValue(req.ChainType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Type {
+	case LinkTypeAddress:
+		addressReq := NewCreateAddressLinkRequest(address, chainType)
+		addressReq.CreationOptions = opts
+		return h.HandleCreateAddressLinkRequest(ctx, addressReq)
+
+	case LinkTypeViewProfile:
+		viewProfileReq := NewCreateViewProfileLinkRequest(address, chainType)
+		viewProfileReq.CreationOptions = opts
+		return h.HandleCreateViewProfileLinkRequest(ctx, viewProfileReq)
+
+	case LinkTypeSend:
+		amount := sdk.NewCoins()
+		if req.Amount != "" {
+			amount, err = parseAmountValue(req.Amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+		sendReq := NewCreateSendLinkRequest(address, amount, chainType)
+		sendReq.CreationOptions = opts
+		return h.HandleCreateSendLinkRequest(ctx, sendReq)
+
+	default:
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidLinkType, "invalid link type").WithParam("type")
+	}
+}
+
+// HandleRenderQRCodeRequest renders the given deep link as a QR code using the given options
+func (h *Handler) HandleRenderQRCodeRequest(deepLink string, opts *qrcode.Options) ([]byte, error) {
+	image, err := qrcode.Encode(deepLink, opts)
+	if err != nil {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidQROptions, err.Error())
+	}
+
+	return image, nil
+}
+
+// HandleGetAddressLinksRequest handles the given GetAddressLinksRequest returning the links created for the address
+func (h *Handler) HandleGetAddressLinksRequest(req *GetAddressLinksRequest) (*GetAddressLinksResponse, error) {
+	links, total, err := h.db.GetAddressLinks(strings.ToLower(req.Address), req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGetAddressLinksResponse(links, req.Pagination, total), nil
+}
+
+// HandleGetLinkConfigRequest handles the given GetLinkConfigRequest returning the link config or an error.
+// The outcome of each request is tracked
+func (h *Handler) HandleGetLinkConfigRequest(ctx context.Context, req *GetLinkConfigRequest) (*GetLinkConfigResponse, error) {
+	res, err := h.handleGetLinkConfigRequest(ctx, req)
+	h.tracker.Track(analytics.NewEvent(analytics.EventGetLinkConfig, "", "", req.ClientKey, err))
+	return res, err
+}
+
+// handleGetLinkConfigRequest returns the configuration of the link requested using the given GetLinkConfigRequest
+func (h *Handler) handleGetLinkConfigRequest(ctx context.Context, req *GetLinkConfigRequest) (*GetLinkConfigResponse, error) {
+	res, err := h.getLinkConfig(ctx, req.Url)
 	if err != nil {
 		return nil, err
 	}
 
 	if res == nil {
-		return nil, utils.WrapErr(http.StatusNotFound, "link not found")
+		return nil, utils.WrapErr(http.StatusNotFound, utils.ErrCodeLinkNotFound, "link not found").WithParam("url")
+	}
+
+	return NewGetLinkConfigResponse(req.Url, res), nil
+}
+
+// getLinkConfig returns the configuration of the link having the given URL, or nil if the link does not exist.
+// The configurations are read from the cache when possible, and fetched from Caerus otherwise
+func (h *Handler) getLinkConfig(ctx context.Context, url string) (*caerustypes.LinkConfig, error) {
+	ctx, span := tracer.Start(ctx, "getLinkConfig")
+	defer span.End()
+
+	config, found := h.configCache.Get(url)
+	span.SetAttributes(attribute.Bool("cache.hit", found))
+	if found {
+		return config, nil
+	}
+
+	config, err := h.caerus.GetLinkConfig(ctx, url)
+	if err != nil {
+		return nil, err
 	}
 
-	return NewGetLinkConfigResponse(url, res), nil
+	h.configCache.Set(url, config)
+	return config, nil
 }
@@ -1,37 +1,68 @@
 package links
 
 import (
+	"fmt"
+	"image"
 	"net/http"
+	"strconv"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	caeruslinks "github.com/desmos-labs/caerus/routes/links"
 	"github.com/gin-gonic/gin"
 
+	"github.com/desmos-labs/dpm-apis/authentication"
+	"github.com/desmos-labs/dpm-apis/qrcode"
+	"github.com/desmos-labs/dpm-apis/ratelimit"
 	"github.com/desmos-labs/dpm-apis/routes"
+	"github.com/desmos-labs/dpm-apis/types"
 	"github.com/desmos-labs/dpm-apis/utils"
 )
 
 const (
+	AddressKey   = "address"
 	ChainTypeKey = "chain_type"
 	AmountKey    = "amount"
+	ForceNewKey  = "force_new"
+	OffsetKey    = "offset"
+	LimitKey     = "limit"
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	FormatKey   = "format"
+	QRFormatKey = "qr_format"
+	QRSizeKey   = "qr_size"
+	QRMarginKey = "qr_margin"
+	QRLevelKey  = "qr_level"
+	QRLogoKey   = "qr_logo"
+
+	FormatJSON = "json"
+	FormatQR   = "qr"
 )
 
 func RegisterWithContext(ctx routes.Context) {
-	Register(ctx.Router, NewHandler(ctx.Caerus))
+	cfg := NewConfigFromEnvVariables()
+	cfg.CreationTimeout = ctx.Caerus.RPCTimeout()
+	Register(ctx.Router, NewHandler(cfg, ctx.Caerus, ctx.LinkConfigCache, ctx.Database, ctx.Analytics), ctx.Authenticator, ctx.RateLimiter)
 }
 
-// Register registers all the routes that allow to perform links-related operations
-func Register(router *gin.Engine, handler *Handler) {
+// Register registers all the routes that allow to perform links-related operations.
+// All the routes are protected using the given authenticator. The routes that are bound to an address can also be
+// used by the owner of such address after logging in with their wallet.
+// All the routes are rate limited using the given limiter, with the creation routes having a stricter policy and the
+// batch route being limited by the number of requested links
+func Register(router *gin.Engine, handler *Handler, authenticator *authentication.Authenticator, limiter *ratelimit.Limiter) {
 	router.
-		GET("/deep-links/config", func(context *gin.Context) {
+		GET("/deep-links/config", authenticator.RequireScope(authentication.ScopeReadLinks), limiter.Limit(ratelimit.PolicyReadLinks), func(context *gin.Context) {
 			deepLinkURL, exists := context.GetQuery("url")
 			if !exists {
-				utils.HandleError(context, utils.WrapErr(http.StatusBadRequest, "missing url param"))
+				utils.HandleError(context, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeMissingParam, "missing url param").WithParam("url"))
 				return
 			}
 
-			res, err := handler.HandleGetLinkConfigRequest(deepLinkURL)
+			req := NewGetLinkConfigRequest(deepLinkURL, utils.GetAPIKeyID(context))
+			res, err := handler.HandleGetLinkConfigRequest(context.Request.Context(), req)
 			if err != nil {
 				utils.HandleError(context, err)
 				return
@@ -40,83 +71,226 @@ func Register(router *gin.Engine, handler *Handler) {
 			context.JSON(http.StatusOK, res)
 		})
 
-	router.Group("/deep-links/:address").
+	router.
+		POST("/deep-links", authenticator.RequireScope(authentication.ScopeCreateLinks), limiter.Limit(ratelimit.PolicyCreateLinks), func(c *gin.Context) {
+			// Build the request
+			qrOptions, err := parseQRCodeOptions(c, handler.cfg.QRLogo)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+			creationOptions, err := parseCreationOptions(c)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+			var req CreateLinkRequest
+			err = c.ShouldBindJSON(&req)
+			if err != nil {
+				utils.HandleError(c, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidRequestBody, "invalid request body"))
+				return
+			}
+			req.CreationOptions = creationOptions
+
+			// Handle the request
+			res, err := handler.HandleCreateLinkRequest(c.Request.Context(), &req)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+
+			// Return the response
+			sendLinkResponse(c, handler, qrOptions, res)
+		})
+
+	router.
+		POST("/deep-links/batch", authenticator.RequireScope(authentication.ScopeCreateLinks), func(c *gin.Context) {
+			// Build the request
+			var req CreateLinksBatchRequest
+			err := c.ShouldBindJSON(&req)
+			if err != nil {
+				utils.HandleError(c, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidRequestBody, "invalid request body"))
+				return
+			}
+			req.CreatorKey = utils.GetAPIKeyID(c)
+
+			// Charge one token for each requested link, so that the batches cannot be used to bypass the limits.
+			// The batches that are empty or too big are rejected by the handler, so they cost a single token
+			cost := len(req.Requests)
+			if cost == 0 || cost > handler.cfg.BatchMaxSize {
+				cost = 1
+			}
+			if !limiter.Take(c, ratelimit.PolicyCreateLinksBatch, cost) {
+				return
+			}
+
+			// Handle the request
+			res, err := handler.HandleCreateLinksBatchRequest(c.Request.Context(), &req)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+
+			// Return the response
+			c.JSON(http.StatusOK, res)
+		})
+
+	router.
+		GET("/addresses/:address/deep-links", authenticator.RequireScopeOrWallet(authentication.ScopeReadLinks), limiter.Limit(ratelimit.PolicyReadLinks), func(c *gin.Context) {
+			// Build the request
+			address, err := parseAddress(c)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+			err = authorizeAddress(c, address)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+			pagination, err := parsePagination(c)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+			req := NewGetAddressLinksRequest(address, pagination)
+
+			// Handle the request
+			res, err := handler.HandleGetAddressLinksRequest(req)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
+
+			// Return the response
+			c.JSON(http.StatusOK, res)
+		})
+
+	router.Group("/deep-links/:address", authenticator.RequireScopeOrWallet(authentication.ScopeCreateLinks), limiter.Limit(ratelimit.PolicyCreateLinks)).
 		GET("", func(c *gin.Context) {
 			// Build the request
+			qrOptions, err := parseQRCodeOptions(c, handler.cfg.QRLogo)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			address, err := parseAddress(c)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
+			err = authorizeAddress(c, address)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			chainType, err := parseChainType(c)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
+			creationOptions, err := parseCreationOptions(c)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			req := NewCreateAddressLinkRequest(address, chainType)
+			req.CreationOptions = creationOptions
 
 			// Handle the request
-			res, err := handler.HandleCreateAddressLinkRequest(req)
+			res, err := handler.HandleCreateAddressLinkRequest(c.Request.Context(), req)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
 
 			// Return the response
-			c.JSON(http.StatusOK, res)
+			sendLinkResponse(c, handler, qrOptions, res)
 		}).
 		GET("/view-profile", func(c *gin.Context) {
 			// Build the request
+			qrOptions, err := parseQRCodeOptions(c, handler.cfg.QRLogo)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			address, err := parseAddress(c)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
+			err = authorizeAddress(c, address)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			chainType, err := parseChainType(c)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
+			creationOptions, err := parseCreationOptions(c)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			req := NewCreateViewProfileLinkRequest(address, chainType)
+			req.CreationOptions = creationOptions
 
 			// Handle the request
-			res, err := handler.HandleCreateViewProfileLinkRequest(req)
+			res, err := handler.HandleCreateViewProfileLinkRequest(c.Request.Context(), req)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
 
 			// Return the response
-			c.JSON(http.StatusOK, res)
+			sendLinkResponse(c, handler, qrOptions, res)
 		}).
 		GET("/send", func(c *gin.Context) {
 			// Build the request
+			qrOptions, err := parseQRCodeOptions(c, handler.cfg.QRLogo)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			address, err := parseAddress(c)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
+			err = authorizeAddress(c, address)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			chainType, err := parseChainType(c)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
+			creationOptions, err := parseCreationOptions(c)
+			if err != nil {
+				utils.HandleError(c, err)
+				return
+			}
 			amount, err := parseAmount(c)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
 			req := NewCreateSendLinkRequest(address, amount, chainType)
+			req.CreationOptions = creationOptions
 
 			// Handle the request
-			res, err := handler.HandleCreateSendLinkRequest(req)
+			res, err := handler.HandleCreateSendLinkRequest(c.Request.Context(), req)
 			if err != nil {
 				utils.HandleError(c, err)
 				return
 			}
 
 			// Return the response
-			c.JSON(http.StatusOK, res)
+			sendLinkResponse(c, handler, qrOptions, res)
 		})
 }
 
@@ -125,31 +299,51 @@ func Register(router *gin.Engine, handler *Handler) {
 // string (es. "desmos1...").
 // If the specified address is not valid, it returns an error
 func parseAddress(context *gin.Context) (string, error) {
-	address := context.Param("address")
+	return parseAddressValue(context.Param(AddressKey))
+}
+
+// parseAddressValue parses the given value as a Bech32 address, returning an error if it is not valid
+func parseAddressValue(address string) (string, error) {
 	if address == "" {
-		return "", utils.WrapErr(http.StatusBadRequest, "invalid address")
+		return "", utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidAddress, "invalid address").WithParam(AddressKey)
 	}
 
 	_, err := sdk.AccAddressFromBech32(address)
 	if err != nil {
-		return "", utils.WrapErr(http.StatusBadRequest, "invalid address")
+		return "", utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidAddress, "invalid address").WithParam(AddressKey)
 	}
 
 	return address, nil
 }
 
+// authorizeAddress makes sure that, if the request has been authenticated using a wallet session token,
+// the given address is the one owned by the authenticated user
+func authorizeAddress(context *gin.Context, address string) error {
+	walletAddress := utils.GetWalletAddress(context)
+	if walletAddress != "" && walletAddress != address {
+		return utils.WrapErr(http.StatusForbidden, utils.ErrCodeAddressNotOwned, "operation allowed only on your own address").WithParam(AddressKey)
+	}
+	return nil
+}
+
 // parseChainType returns the chain type that has been specified inside the given context.
 // It expects the chain type to be specified using the ChainTypeKey in the form of a
 // string (either "mainnet" or "testnet").
 func parseChainType(context *gin.Context) (caeruslinks.ChainType, error) {
 	chainType, exists := context.GetQuery(ChainTypeKey)
 	if !exists {
-		return caeruslinks.ChainType_UNDEFINED, utils.WrapErr(http.StatusBadRequest, "invalid chain type")
+		return caeruslinks.ChainType_UNDEFINED, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidChainType, "invalid chain type").WithParam(ChainTypeKey)
 	}
 
+	return parseChainTypeValue(chainType)
+}
+
+// parseChainTypeValue parses the given value as a chain type (either "mainnet" or "testnet"),
+// returning an error if it is not valid
+func parseChainTypeValue(chainType string) (caeruslinks.ChainType, error) {
 	chainTypeValue, ok := caeruslinks.ChainType_value[strings.ToUpper(chainType)]
 	if !ok {
-		return caeruslinks.ChainType_UNDEFINED, utils.WrapErr(http.StatusBadRequest, "invalid chain type")
+		return caeruslinks.ChainType_UNDEFINED, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidChainType, "invalid chain type").WithParam(ChainTypeKey)
 	}
 
 	return caeruslinks.ChainType(chainTypeValue), nil
@@ -165,10 +359,131 @@ func parseAmount(context *gin.Context) (sdk.Coins, error) {
 		return sdk.NewCoins(), nil
 	}
 
+	return parseAmountValue(amountValue)
+}
+
+// parseAmountValue parses the given value as an amount of coins (e.g. "1000udaric"),
+// returning an error if it is not valid
+func parseAmountValue(amountValue string) (sdk.Coins, error) {
 	amount, err := sdk.ParseCoinsNormalized(amountValue)
 	if err != nil {
-		return sdk.NewCoins(), utils.WrapErr(http.StatusBadRequest, "invalid amount")
+		return sdk.NewCoins(), utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidAmount, "invalid amount").WithParam(AmountKey)
 	}
 
 	return amount, nil
 }
+
+// parseCreationOptions returns the link creation options that have been specified inside the given context.
+// It expects the ForceNewKey to be specified in the form of a boolean string (e.g. "true"), and reads the
+// creator key from the API key used to authenticate the request, if any
+func parseCreationOptions(context *gin.Context) (CreationOptions, error) {
+	forceNew := false
+	if forceNewValue, exists := context.GetQuery(ForceNewKey); exists {
+		value, err := strconv.ParseBool(forceNewValue)
+		if err != nil {
+			return CreationOptions{}, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidParam, "invalid force_new value").WithParam(ForceNewKey)
+		}
+		forceNew = value
+	}
+
+	return NewCreationOptions(forceNew, utils.GetAPIKeyID(context)), nil
+}
+
+// parsePagination returns the pagination that has been specified inside the given context.
+// It expects the OffsetKey and LimitKey to be specified as non-negative integers, with the limit being at most
+// MaxLimit. If not specified, the offset defaults to 0 and the limit defaults to DefaultLimit
+func parsePagination(context *gin.Context) (*types.Pagination, error) {
+	offset, err := strconv.ParseUint(context.DefaultQuery(OffsetKey, "0"), 10, 64)
+	if err != nil {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidPagination, "invalid offset").WithParam(OffsetKey)
+	}
+
+	limit, err := strconv.ParseUint(context.DefaultQuery(LimitKey, strconv.Itoa(DefaultLimit)), 10, 64)
+	if err != nil || limit == 0 || limit > MaxLimit {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidPagination, fmt.Sprintf("invalid limit: must be between 1 and %d", MaxLimit)).WithParam(LimitKey)
+	}
+
+	return types.NewPagination(offset, limit), nil
+}
+
+// parseQRCodeOptions returns the QR code options that have been specified inside the given context.
+// If the FormatKey is not set to FormatQR, it returns nil meaning that the response should be returned as JSON.
+// Otherwise, it reads the QR code options from the QRFormatKey ("png" or "svg"), QRSizeKey (pixels),
+// QRMarginKey (modules), QRLevelKey ("L", "M", "Q" or "H") and QRLogoKey (boolean) params.
+// If the logo is requested but the given one is nil, it returns an error
+func parseQRCodeOptions(context *gin.Context, logo image.Image) (*qrcode.Options, error) {
+	format := context.DefaultQuery(FormatKey, FormatJSON)
+	switch format {
+	case FormatJSON:
+		return nil, nil
+	case FormatQR:
+		break
+	default:
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidFormat, "invalid format").WithParam(FormatKey)
+	}
+
+	options := qrcode.DefaultOptions()
+	options.Format = strings.ToLower(context.DefaultQuery(QRFormatKey, options.Format))
+
+	if sizeValue, exists := context.GetQuery(QRSizeKey); exists {
+		size, err := strconv.Atoi(sizeValue)
+		if err != nil {
+			return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidQROptions, "invalid QR code size").WithParam(QRSizeKey)
+		}
+		options.Size = size
+	}
+
+	if marginValue, exists := context.GetQuery(QRMarginKey); exists {
+		margin, err := strconv.Atoi(marginValue)
+		if err != nil {
+			return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidQROptions, "invalid QR code margin").WithParam(QRMarginKey)
+		}
+		options.Margin = margin
+	}
+
+	if levelValue, exists := context.GetQuery(QRLevelKey); exists {
+		level, err := qrcode.ParseLevel(levelValue)
+		if err != nil {
+			return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidQROptions, "invalid QR code error correction level").WithParam(QRLevelKey)
+		}
+		options.Level = level
+	}
+
+	if logoValue, exists := context.GetQuery(QRLogoKey); exists {
+		withLogo, err := strconv.ParseBool(logoValue)
+		if err != nil {
+			return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidQROptions, "invalid QR code logo value").WithParam(QRLogoKey)
+		}
+
+		if withLogo {
+			if logo == nil {
+				return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidQROptions, "QR code logo not available").WithParam(QRLogoKey)
+			}
+			options.Logo = logo
+		}
+	}
+
+	err := options.Validate()
+	if err != nil {
+		return nil, utils.WrapErr(http.StatusBadRequest, utils.ErrCodeInvalidQROptions, err.Error())
+	}
+
+	return options, nil
+}
+
+// sendLinkResponse sends the given response using the format that has been requested.
+// If the QR code options are nil the response is sent as JSON, otherwise the deep link is rendered as a QR code
+func sendLinkResponse(context *gin.Context, handler *Handler, qrOptions *qrcode.Options, res *CreateLinkResponse) {
+	if qrOptions == nil {
+		context.JSON(http.StatusOK, res)
+		return
+	}
+
+	qrCode, err := handler.HandleRenderQRCodeRequest(res.DeepLink, qrOptions)
+	if err != nil {
+		utils.HandleError(context, err)
+		return
+	}
+
+	context.Data(http.StatusOK, qrOptions.ContentType(), qrCode)
+}
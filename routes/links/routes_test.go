@@ -0,0 +1,495 @@
+package links_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	caeruslinks "github.com/desmos-labs/caerus/routes/links"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/desmos-labs/dpm-apis/analytics"
+	"github.com/desmos-labs/dpm-apis/database/memory"
+	"github.com/desmos-labs/dpm-apis/internal/testutil"
+	"github.com/desmos-labs/dpm-apis/routes/links"
+	"github.com/desmos-labs/dpm-apis/types"
+	"github.com/desmos-labs/dpm-apis/utils"
+)
+
+func TestRoutesTestSuite(t *testing.T) {
+	suite.Run(t, new(RoutesTestSuite))
+}
+
+// RoutesTestSuite tests all the links routes end to end, sending the requests to the real router
+// which calls a fake Caerus server through a real gRPC client
+type RoutesTestSuite struct {
+	suite.Suite
+
+	address string
+
+	fakeCaerus *testutil.FakeCaerus
+	tracker    *trackerMock
+	db         links.Database
+	router     *gin.Engine
+}
+
+// trackerMock is a links.Tracker that records all the tracked events
+type trackerMock struct {
+	mu     sync.Mutex
+	events []*analytics.Event
+}
+
+func (t *trackerMock) Track(event *analytics.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+// Events returns all the events tracked so far
+func (t *trackerMock) Events() []*analytics.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*analytics.Event{}, t.events...)
+}
+
+// failingDatabase is a links.Database that fails to store any link
+type failingDatabase struct {
+	*memory.Database
+}
+
+func (failingDatabase) SaveLinkURL(string, string) error {
+	return fmt.Errorf("database unavailable")
+}
+
+func (failingDatabase) SaveCreatedLink(*types.CreatedLink) error {
+	return fmt.Errorf("database unavailable")
+}
+
+// blockingCaerus is a links.CaerusClient that blocks the creation of the address links until it is released,
+// and fails them if their context has been canceled in the meantime
+type blockingCaerus struct {
+	links.CaerusClient
+
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func newBlockingCaerus(client links.CaerusClient) *blockingCaerus {
+	return &blockingCaerus{
+		CaerusClient: client,
+		started:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+}
+
+func (b *blockingCaerus) CreateAddressLink(ctx context.Context, request *caeruslinks.CreateAddressLinkRequest) (*caeruslinks.CreateLinkResponse, error) {
+	if b.calls.Add(1) == 1 {
+		close(b.started)
+	}
+
+	<-b.release
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return b.CaerusClient.CreateAddressLink(ctx, request)
+}
+
+func (suite *RoutesTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	suite.address = testutil.TestAddress()
+}
+
+func (suite *RoutesTestSuite) SetupTest() {
+	fakeCaerus, err := testutil.StartFakeCaerus()
+	suite.Require().NoError(err)
+	suite.fakeCaerus = fakeCaerus
+
+	suite.tracker = &trackerMock{}
+	suite.db = memory.NewDatabase()
+	suite.router = suite.buildRouter(testutil.CaerusAPIKey)
+}
+
+func (suite *RoutesTestSuite) TearDownTest() {
+	suite.Require().NoError(suite.fakeCaerus.Stop())
+}
+
+// buildRouter builds a new router exposing the links routes, which calls Caerus using the given API key
+func (suite *RoutesTestSuite) buildRouter(caerusKey string) *gin.Engine {
+	router, err := testutil.NewLinksRouter(suite.fakeCaerus.Client(caerusKey), suite.db, suite.tracker)
+	suite.Require().NoError(err)
+	return router
+}
+
+// request performs the given request against the router, authenticating it using the test API key
+func (suite *RoutesTestSuite) request(method string, path string, body interface{}) *httptest.ResponseRecorder {
+	var bodyBz []byte
+	if body != nil {
+		var err error
+		bodyBz, err = json.Marshal(body)
+		suite.Require().NoError(err)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(bodyBz))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", testutil.APIKey))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// createLink performs the given link creation request, making sure it succeeds, and returns the created link
+func (suite *RoutesTestSuite) createLink(method string, path string, body interface{}) string {
+	res := suite.request(method, path, body)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var linkRes links.CreateLinkResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &linkRes))
+	return linkRes.DeepLink
+}
+
+// getCustomData returns the custom data of the link having the given URL, making sure the link exists
+func (suite *RoutesTestSuite) getCustomData(deepLink string) map[string]interface{} {
+	res := suite.request(http.MethodGet, "/deep-links/config?url="+url.QueryEscape(deepLink), nil)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var configRes links.GetLinkConfigResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &configRes))
+	suite.Require().Equal(deepLink, configRes.DeepLink)
+
+	var customData map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(configRes.Config.CustomData, &customData))
+	return customData
+}
+
+// requireError makes sure that the given response contains an error having the given status and code
+func (suite *RoutesTestSuite) requireError(res *httptest.ResponseRecorder, status int, code string) {
+	suite.Require().Equal(status, res.Code, res.Body.String())
+	suite.Require().Equal(utils.ProblemContentType, res.Header().Get("Content-Type"))
+
+	var problem utils.ProblemResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &problem))
+	suite.Require().Equal(code, problem.Code)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+func (suite *RoutesTestSuite) TestCreateAddressLink() {
+	path := fmt.Sprintf("/deep-links/%s?chain_type=testnet", suite.address)
+
+	deepLink := suite.createLink(http.MethodGet, path, nil)
+	suite.Require().True(strings.HasPrefix(deepLink, testutil.BaseURL+"/"))
+
+	customData := suite.getCustomData(deepLink)
+	suite.Require().Equal(suite.address, customData["address"])
+	suite.Require().Equal("testnet", customData["chain_type"])
+
+	// Equal requests should return the same link, unless a new one is requested
+	suite.Require().Equal(deepLink, suite.createLink(http.MethodGet, path, nil))
+	newLink := suite.createLink(http.MethodGet, path+"&force_new=true", nil)
+	suite.Require().True(strings.HasPrefix(newLink, testutil.BaseURL+"/"))
+	suite.Require().NotEqual(deepLink, newLink)
+}
+
+func (suite *RoutesTestSuite) TestCreateAddressLink_CaerusRestart() {
+	path := fmt.Sprintf("/deep-links/%s?chain_type=testnet", suite.address)
+	deepLink := suite.createLink(http.MethodGet, path, nil)
+
+	// Restart Caerus while keeping the stored links, as it happens when using a persistent database in mock mode
+	suite.Require().NoError(suite.fakeCaerus.Stop())
+	fakeCaerus, err := testutil.StartFakeCaerus()
+	suite.Require().NoError(err)
+	suite.fakeCaerus = fakeCaerus
+	suite.router = suite.buildRouter(testutil.CaerusAPIKey)
+
+	// The stored link should be reused, while new links should never collide with it
+	suite.Require().Equal(deepLink, suite.createLink(http.MethodGet, path, nil))
+	newLink := suite.createLink(http.MethodGet, path+"&force_new=true", nil)
+	suite.Require().NotEqual(deepLink, newLink)
+
+	res := suite.request(http.MethodGet, fmt.Sprintf("/addresses/%s/deep-links", suite.address), nil)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var linksRes links.GetAddressLinksResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &linksRes))
+	suite.Require().Equal(uint64(2), linksRes.Pagination.Total)
+}
+
+func (suite *RoutesTestSuite) TestCreateAddressLink_ConcurrentRequests() {
+	path := fmt.Sprintf("/deep-links/%s?chain_type=mainnet", suite.address)
+
+	// Perform equal requests at the same time, which should all share a single link
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 10)
+	for i := range responses {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			responses[index] = suite.request(http.MethodGet, path, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, res := range responses {
+		suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+	}
+
+	res := suite.request(http.MethodGet, fmt.Sprintf("/addresses/%s/deep-links", suite.address), nil)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var linksRes links.GetAddressLinksResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &linksRes))
+	suite.Require().Equal(uint64(1), linksRes.Pagination.Total)
+}
+
+func (suite *RoutesTestSuite) TestCreateAddressLink_FirstRequestCanceled() {
+	caerusClient := newBlockingCaerus(suite.fakeCaerus.Client(testutil.CaerusAPIKey))
+	router, err := testutil.NewLinksRouter(caerusClient, suite.db, suite.tracker)
+	suite.Require().NoError(err)
+
+	path := fmt.Sprintf("/deep-links/%s?chain_type=mainnet", suite.address)
+	serve := func(ctx context.Context) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", testutil.APIKey))
+
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+		return res
+	}
+
+	// Start the first request, and cancel it while its link is being created
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serve(firstCtx)
+	}()
+	<-caerusClient.started
+
+	// Start an equal request, which should wait for the link being created
+	var secondRes *httptest.ResponseRecorder
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		secondRes = serve(context.Background())
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancelFirst()
+	close(caerusClient.release)
+	wg.Wait()
+
+	// The creation should not have been affected by the first request going away
+	suite.Require().Equal(http.StatusOK, secondRes.Code, secondRes.Body.String())
+	suite.Require().Equal(int32(1), caerusClient.calls.Load())
+
+	var linkRes links.CreateLinkResponse
+	suite.Require().NoError(json.Unmarshal(secondRes.Body.Bytes(), &linkRes))
+	suite.Require().True(strings.HasPrefix(linkRes.DeepLink, testutil.BaseURL+"/"))
+}
+
+func (suite *RoutesTestSuite) TestCreateAddressLink_DatabaseFailure() {
+	suite.db = failingDatabase{memory.NewDatabase()}
+	suite.router = suite.buildRouter(testutil.CaerusAPIKey)
+
+	// The created link should be returned even if it cannot be stored
+	deepLink := suite.createLink(http.MethodGet, fmt.Sprintf("/deep-links/%s?chain_type=mainnet", suite.address), nil)
+	suite.Require().NotEmpty(deepLink)
+}
+
+func (suite *RoutesTestSuite) TestCreateAddressLink_InvalidRequest() {
+	res := suite.request(http.MethodGet, "/deep-links/invalid?chain_type=testnet", nil)
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeInvalidAddress)
+
+	res = suite.request(http.MethodGet, fmt.Sprintf("/deep-links/%s", suite.address), nil)
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeInvalidChainType)
+}
+
+func (suite *RoutesTestSuite) TestCreateAddressLink_QRCode() {
+	res := suite.request(http.MethodGet, fmt.Sprintf("/deep-links/%s?chain_type=mainnet&format=qr", suite.address), nil)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+	suite.Require().Equal("image/png", res.Header().Get("Content-Type"))
+	suite.Require().NotEmpty(res.Body.Bytes())
+}
+
+func (suite *RoutesTestSuite) TestCreateAddressLink_QRCodeTooSmall() {
+	path := fmt.Sprintf("/deep-links/%s?chain_type=mainnet&format=qr&qr_size=64&qr_level=H", suite.address)
+	res := suite.request(http.MethodGet, path, nil)
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeInvalidQROptions)
+
+	// The request should have been rejected before creating the link
+	res = suite.request(http.MethodGet, fmt.Sprintf("/addresses/%s/deep-links", suite.address), nil)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var linksRes links.GetAddressLinksResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &linksRes))
+	suite.Require().Zero(linksRes.Pagination.Total)
+}
+
+func (suite *RoutesTestSuite) TestCreateViewProfileLink() {
+	deepLink := suite.createLink(http.MethodGet, fmt.Sprintf("/deep-links/%s/view-profile?chain_type=mainnet", suite.address), nil)
+
+	customData := suite.getCustomData(deepLink)
+	suite.Require().Equal("view_profile", customData["action"])
+	suite.Require().Equal(suite.address, customData["address"])
+	suite.Require().Equal("mainnet", customData["chain_type"])
+}
+
+func (suite *RoutesTestSuite) TestCreateSendLink() {
+	deepLink := suite.createLink(http.MethodGet, fmt.Sprintf("/deep-links/%s/send?chain_type=mainnet&amount=10udsm", suite.address), nil)
+
+	customData := suite.getCustomData(deepLink)
+	suite.Require().Equal("send_tokens", customData["action"])
+	suite.Require().Equal(suite.address, customData["address"])
+	suite.Require().Equal("10udsm", customData["amount"])
+
+	res := suite.request(http.MethodGet, fmt.Sprintf("/deep-links/%s/send?chain_type=mainnet&amount=invalid", suite.address), nil)
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeInvalidAmount)
+}
+
+func (suite *RoutesTestSuite) TestCreateCustomLink() {
+	deepLink := suite.createLink(http.MethodPost, "/deep-links", map[string]interface{}{
+		"data":     map[string]interface{}{"key": "value"},
+		"campaign": "test",
+	})
+
+	customData := suite.getCustomData(deepLink)
+	suite.Require().Equal("value", customData["key"])
+	suite.Require().Equal("test", customData["~campaign"])
+
+	res := suite.request(http.MethodPost, "/deep-links", map[string]interface{}{})
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeInvalidLinkConfig)
+}
+
+func (suite *RoutesTestSuite) TestCreateLinksBatch() {
+	res := suite.request(http.MethodPost, "/deep-links/batch", map[string]interface{}{
+		"requests": []map[string]interface{}{
+			{"type": links.LinkTypeAddress, "address": suite.address, "chain_type": "mainnet"},
+			{"type": links.LinkTypeSend, "address": suite.address, "chain_type": "mainnet", "amount": "10udsm"},
+			{"type": links.LinkTypeCustom, "config": map[string]interface{}{"data": map[string]interface{}{"key": "value"}}},
+			{"type": links.LinkTypeViewProfile, "address": "invalid", "chain_type": "mainnet"},
+		},
+	})
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var batchRes links.CreateLinksBatchResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &batchRes))
+	suite.Require().Len(batchRes.Results, 4)
+
+	for _, result := range batchRes.Results[:3] {
+		suite.Require().NotEmpty(result.DeepLink)
+		suite.Require().NotEmpty(suite.getCustomData(result.DeepLink))
+	}
+
+	suite.Require().Empty(batchRes.Results[3].DeepLink)
+	suite.Require().Equal(http.StatusBadRequest, batchRes.Results[3].Status)
+	suite.Require().Equal(utils.ErrCodeInvalidAddress, batchRes.Results[3].Code)
+	suite.Require().Equal(links.AddressKey, batchRes.Results[3].Param)
+}
+
+func (suite *RoutesTestSuite) TestGetAddressLinks() {
+	addressLink := suite.createLink(http.MethodGet, fmt.Sprintf("/deep-links/%s?chain_type=mainnet", suite.address), nil)
+	sendLink := suite.createLink(http.MethodGet, fmt.Sprintf("/deep-links/%s/send?chain_type=mainnet", suite.address), nil)
+
+	res := suite.request(http.MethodGet, fmt.Sprintf("/addresses/%s/deep-links?limit=1", suite.address), nil)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var linksRes links.GetAddressLinksResponse
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &linksRes))
+	suite.Require().Equal(uint64(2), linksRes.Pagination.Total)
+	suite.Require().Len(linksRes.Links, 1)
+	suite.Require().Equal(sendLink, linksRes.Links[0].URL)
+
+	res = suite.request(http.MethodGet, fmt.Sprintf("/addresses/%s/deep-links?offset=1", suite.address), nil)
+	suite.Require().Equal(http.StatusOK, res.Code, res.Body.String())
+	suite.Require().NoError(json.Unmarshal(res.Body.Bytes(), &linksRes))
+	suite.Require().Len(linksRes.Links, 1)
+	suite.Require().Equal(addressLink, linksRes.Links[0].URL)
+}
+
+func (suite *RoutesTestSuite) TestGetLinkConfig_NotFound() {
+	res := suite.request(http.MethodGet, "/deep-links/config?url="+url.QueryEscape(testutil.BaseURL+"/not-found"), nil)
+	suite.requireError(res, http.StatusNotFound, utils.ErrCodeLinkNotFound)
+
+	res = suite.request(http.MethodGet, "/deep-links/config", nil)
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeMissingParam)
+}
+
+func (suite *RoutesTestSuite) TestAnalyticsEvents() {
+	deepLink := suite.createLink(http.MethodGet, fmt.Sprintf("/deep-links/%s/send?chain_type=mainnet", suite.address), nil)
+	suite.getCustomData(deepLink)
+
+	// Failed operations should be tracked along with their error code
+	suite.router = suite.buildRouter("invalid-key")
+	res := suite.request(http.MethodGet, fmt.Sprintf("/deep-links/%s?chain_type=testnet", suite.address), nil)
+	suite.requireError(res, http.StatusBadGateway, utils.ErrCodeUpstreamError)
+
+	events := suite.tracker.Events()
+	suite.Require().Len(events, 3)
+
+	suite.Require().Equal(analytics.EventCreateLink, events[0].Name)
+	suite.Require().Equal(links.LinkTypeSend, events[0].LinkType)
+	suite.Require().Equal("mainnet", events[0].ChainType)
+	suite.Require().Equal(testutil.APIKeyID, events[0].ClientKey)
+	suite.Require().True(events[0].Success)
+
+	suite.Require().Equal(analytics.EventGetLinkConfig, events[1].Name)
+	suite.Require().Equal(testutil.APIKeyID, events[1].ClientKey)
+	suite.Require().True(events[1].Success)
+
+	suite.Require().Equal(analytics.EventCreateLink, events[2].Name)
+	suite.Require().Equal(links.LinkTypeAddress, events[2].LinkType)
+	suite.Require().Equal("testnet", events[2].ChainType)
+	suite.Require().False(events[2].Success)
+	suite.Require().Equal(utils.ErrCodeUpstreamError, events[2].ErrorCode)
+}
+
+func (suite *RoutesTestSuite) TestAnalyticsEvents_InvalidRequests() {
+	// Invalid requests should never be tracked, whatever link they ask for
+	res := suite.request(http.MethodPost, "/deep-links", map[string]interface{}{})
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeInvalidLinkConfig)
+
+	res = suite.request(http.MethodGet, "/deep-links/invalid-address/send?chain_type=mainnet", nil)
+	suite.requireError(res, http.StatusBadRequest, utils.ErrCodeInvalidAddress)
+
+	res = suite.request(http.MethodGet, fmt.Sprintf("/deep-links/%s/view-profile?chain_type=invalid", suite.address), nil)
+	suite.Require().Equal(http.StatusBadRequest, res.Code)
+
+	res = suite.request(http.MethodPost, "/deep-links/batch", map[string]interface{}{
+		"requests": []map[string]interface{}{
+			{"type": links.LinkTypeCustom, "config": map[string]interface{}{}},
+			{"type": links.LinkTypeAddress, "address": "invalid-address", "chain_type": "mainnet"},
+		},
+	})
+	suite.Require().Equal(http.StatusOK, res.Code)
+
+	suite.Require().Empty(suite.tracker.Events())
+}
+
+func (suite *RoutesTestSuite) TestUnauthenticatedRequest() {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/deep-links/%s?chain_type=mainnet", suite.address), nil)
+	res := httptest.NewRecorder()
+	suite.router.ServeHTTP(res, req)
+	suite.requireError(res, http.StatusUnauthorized, utils.ErrCodeUnauthorized)
+}
+
+func (suite *RoutesTestSuite) TestCaerusAPIKeyRejected() {
+	suite.router = suite.buildRouter("invalid-key")
+
+	res := suite.request(http.MethodGet, fmt.Sprintf("/deep-links/%s?chain_type=mainnet", suite.address), nil)
+	suite.requireError(res, http.StatusBadGateway, utils.ErrCodeUpstreamError)
+}
@@ -1,9 +1,20 @@
 package links
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	caeruslinks "github.com/desmos-labs/caerus/routes/links"
 	caerustypes "github.com/desmos-labs/caerus/types"
+
+	"github.com/desmos-labs/dpm-apis/types"
+	"github.com/desmos-labs/dpm-apis/utils"
 )
 
 type CreateAddressLinkRequest struct {
@@ -12,6 +23,9 @@ type CreateAddressLinkRequest struct {
 
 	// ChainType represents the chain type to use to create the link
 	ChainType caeruslinks.ChainType
+
+	// CreationOptions contains the options used while creating the link
+	CreationOptions
 }
 
 func NewCreateAddressLinkRequest(address string, chainType caeruslinks.ChainType) *CreateAddressLinkRequest {
@@ -21,12 +35,19 @@ func NewCreateAddressLinkRequest(address string, chainType caeruslinks.ChainType
 	}
 }
 
+func (r *CreateAddressLinkRequest) getLinkData() (*linkData, error) {
+	return newLinkData(LinkTypeAddress, r.Address, nil, r.ChainType, nil), nil
+}
+
 type CreateViewProfileLinkRequest struct {
 	// Address represents the address of the user for which to create the link
 	Address string
 
 	// ChainType represents the chain for which the link should be created
 	ChainType caeruslinks.ChainType
+
+	// CreationOptions contains the options used while creating the link
+	CreationOptions
 }
 
 func NewCreateViewProfileLinkRequest(address string, chainType caeruslinks.ChainType) *CreateViewProfileLinkRequest {
@@ -36,6 +57,10 @@ func NewCreateViewProfileLinkRequest(address string, chainType caeruslinks.Chain
 	}
 }
 
+func (r *CreateViewProfileLinkRequest) getLinkData() (*linkData, error) {
+	return newLinkData(LinkTypeViewProfile, r.Address, nil, r.ChainType, nil), nil
+}
+
 type CreateSendLinkRequest struct {
 	// Address if the address of the user that should receive the funds
 	Address string
@@ -45,6 +70,9 @@ type CreateSendLinkRequest struct {
 
 	// ChainType represents the chain for which the link should be created
 	ChainType caeruslinks.ChainType
+
+	// CreationOptions contains the options used while creating the link
+	CreationOptions
 }
 
 func NewCreateSendLinkRequest(address string, amount sdk.Coins, chainType caeruslinks.ChainType) *CreateSendLinkRequest {
@@ -55,6 +83,75 @@ func NewCreateSendLinkRequest(address string, amount sdk.Coins, chainType caerus
 	}
 }
 
+func (r *CreateSendLinkRequest) getLinkData() (*linkData, error) {
+	return newLinkData(LinkTypeSend, r.Address, r.Amount, r.ChainType, nil), nil
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// CreationOptions contains the options shared by all the link creation requests
+type CreationOptions struct {
+	// ForceNew tells whether a new link should be created even if one already exists for the same request
+	ForceNew bool
+
+	// CreatorKey represents the identifier of the API key used to create the link, if any
+	CreatorKey string
+}
+
+func NewCreationOptions(forceNew bool, creatorKey string) CreationOptions {
+	return CreationOptions{
+		ForceNew:   forceNew,
+		CreatorKey: creatorKey,
+	}
+}
+
+// linkData contains the data that identifies a link creation request
+type linkData struct {
+	Action    string      `json:"action"`
+	Address   string      `json:"address,omitempty"`
+	Amount    string      `json:"amount,omitempty"`
+	ChainType string      `json:"chain_type,omitempty"`
+	Metadata  interface{} `json:"metadata,omitempty"`
+}
+
+func newLinkData(
+	action string, address string, amount sdk.Coins, chainType caeruslinks.ChainType, metadata interface{},
+) *linkData {
+	data := &linkData{
+		Action:   action,
+		Address:  strings.ToLower(address),
+		Metadata: metadata,
+	}
+
+	// Sort the coins and remove the zero ones so that equal amounts always have the same representation
+	if !amount.Empty() {
+		data.Amount = sdk.NewCoins(amount...).String()
+	}
+
+	if chainType != caeruslinks.ChainType_UNDEFINED {
+		data.ChainType = strings.ToLower(chainType.String())
+	}
+
+	return data
+}
+
+// IdempotencyKey returns the key that identifies all the requests having the same data.
+// Since JSON objects keys are always sorted during serialization, equal data always produce the same key
+func (d *linkData) IdempotencyKey() (string, error) {
+	bz, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(bz)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// CreatedLink returns the types.CreatedLink representing the link having the given URL created from this data
+func (d *linkData) CreatedLink(url string, creatorKey string, creationTime time.Time) *types.CreatedLink {
+	return types.NewCreatedLink(url, d.Action, d.Address, d.Amount, d.ChainType, creatorKey, creationTime)
+}
+
 // CreateLinkResponse represents the response returned when a link is created
 type CreateLinkResponse struct {
 	// DeepLink represents the URL of the generated deep link
@@ -70,11 +167,15 @@ func NewCreateLinkResponse(deepLink string) *CreateLinkResponse {
 // GetLinkConfigRequest represents the request sent to get the link configuration
 type GetLinkConfigRequest struct {
 	Url string
+
+	// ClientKey represents the identifier of the API key used to perform the request, if any
+	ClientKey string
 }
 
-func NewGetLinkConfigRequest(url string) *GetLinkConfigRequest {
+func NewGetLinkConfigRequest(url string, clientKey string) *GetLinkConfigRequest {
 	return &GetLinkConfigRequest{
-		Url: url,
+		Url:       url,
+		ClientKey: clientKey,
 	}
 }
 
@@ -90,3 +191,340 @@ func NewGetLinkConfigResponse(deepLink string, config *caerustypes.LinkConfig) *
 		Config:   config,
 	}
 }
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// CreateLinkRequest represents the request sent to create a deep link having a custom configuration
+type CreateLinkRequest struct {
+	// Data contains the custom data that will be associated to the link
+	Data map[string]interface{} `json:"data"`
+
+	// OpenGraph contains the Open Graph properties that will be added to the link
+	OpenGraph *OpenGraphConfig `json:"open_graph,omitempty"`
+
+	// Twitter contains the Twitter properties that will be added to the link
+	Twitter *TwitterConfig `json:"twitter,omitempty"`
+
+	// Redirections contains the fallback URLs that will be used based on the device of the user
+	Redirections *RedirectionsConfig `json:"redirections,omitempty"`
+
+	// DeepLinking contains the paths that will be used to open the application based on the device of the user
+	DeepLinking *DeepLinkingConfig `json:"deep_linking,omitempty"`
+
+	// Campaign, Channel, Feature and Tags represent the analytics tags that will be associated to the link
+	Campaign string   `json:"campaign,omitempty"`
+	Channel  string   `json:"channel,omitempty"`
+	Feature  string   `json:"feature,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+
+	// CreationOptions contains the options used while creating the link
+	CreationOptions `json:"-"`
+}
+
+// OpenGraphConfig contains the Open Graph properties of a link
+type OpenGraphConfig struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+// TwitterConfig contains the Twitter properties of a link
+type TwitterConfig struct {
+	CardType    string `json:"card_type,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+// RedirectionsConfig contains the fallback URLs of a link
+type RedirectionsConfig struct {
+	FallbackURL    string `json:"fallback_url,omitempty"`
+	DesktopURL     string `json:"desktop_url,omitempty"`
+	IosURL         string `json:"ios_url,omitempty"`
+	AndroidURL     string `json:"android_url,omitempty"`
+	WebOnly        bool   `json:"web_only,omitempty"`
+	DesktopWebOnly bool   `json:"desktop_web_only,omitempty"`
+	MobileWebOnly  bool   `json:"mobile_web_only,omitempty"`
+}
+
+// DeepLinkingConfig contains the paths used to open the application from a link
+type DeepLinkingConfig struct {
+	DeepLinkPath        string `json:"deep_link_path,omitempty"`
+	AndroidDeepLinkPath string `json:"android_deep_link_path,omitempty"`
+	IosDeepLinkPath     string `json:"ios_deep_link_path,omitempty"`
+	DesktopDeepLinkPath string `json:"desktop_deep_link_path,omitempty"`
+}
+
+// Validate checks whether the request contains a valid link configuration
+func (r *CreateLinkRequest) Validate() error {
+	if len(r.Data) == 0 && r.DeepLinking == nil {
+		return fmt.Errorf("either data or deep_linking must be provided")
+	}
+
+	for key := range r.Data {
+		if strings.HasPrefix(key, "$") || strings.HasPrefix(key, "~") {
+			return fmt.Errorf("invalid data key %s: keys starting with $ or ~ are reserved", key)
+		}
+	}
+
+	if r.OpenGraph != nil {
+		err := validateWebURL("open_graph.image_url", r.OpenGraph.ImageURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Twitter != nil {
+		err := validateWebURL("twitter.image_url", r.Twitter.ImageURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Redirections != nil {
+		redirections := [][2]string{
+			{"redirections.fallback_url", r.Redirections.FallbackURL},
+			{"redirections.desktop_url", r.Redirections.DesktopURL},
+			{"redirections.ios_url", r.Redirections.IosURL},
+			{"redirections.android_url", r.Redirections.AndroidURL},
+		}
+		for _, redirection := range redirections {
+			err := validateWebURL(redirection[0], redirection[1])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tag := range r.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags cannot contain empty values")
+		}
+	}
+
+	return nil
+}
+
+func (r *CreateLinkRequest) getLinkData() (*linkData, error) {
+	config, err := r.LinkConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Custom links are not bound to an address, but the data might still refer to one
+	address, _ := r.Data[caerustypes.DeepLinkAddressKey].(string)
+	return newLinkData(LinkTypeCustom, address, nil, caeruslinks.ChainType_UNDEFINED, config), nil
+}
+
+// validateWebURL makes sure that the given value, if not empty, is a valid website URL
+func validateWebURL(field string, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid %s: must be a valid http or https URL", field)
+	}
+
+	return nil
+}
+
+// LinkConfig returns the caerustypes.LinkConfig that should be used to create the link
+func (r *CreateLinkRequest) LinkConfig() (*caerustypes.LinkConfig, error) {
+	// Build the custom data, adding the analytics tags using the Branch reserved keys
+	customData := make(map[string]interface{}, len(r.Data)+4)
+	for key, value := range r.Data {
+		customData[key] = value
+	}
+	if r.Campaign != "" {
+		customData["~campaign"] = r.Campaign
+	}
+	if r.Channel != "" {
+		customData["~channel"] = r.Channel
+	}
+	if r.Feature != "" {
+		customData["~feature"] = r.Feature
+	}
+	if len(r.Tags) > 0 {
+		customData["~tags"] = r.Tags
+	}
+
+	customDataBz, err := json.Marshal(customData)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &caerustypes.LinkConfig{
+		CustomData: customDataBz,
+	}
+
+	if r.OpenGraph != nil {
+		config.OpenGraph = &caerustypes.OpenGraphConfig{
+			Title:       r.OpenGraph.Title,
+			Description: r.OpenGraph.Description,
+			ImageUrl:    r.OpenGraph.ImageURL,
+		}
+	}
+
+	if r.Twitter != nil {
+		config.Twitter = &caerustypes.TwitterConfig{
+			CardType:    r.Twitter.CardType,
+			Title:       r.Twitter.Title,
+			Description: r.Twitter.Description,
+			ImageUrl:    r.Twitter.ImageURL,
+		}
+	}
+
+	if r.Redirections != nil {
+		config.Redirections = &caerustypes.RedirectionsConfig{
+			FallbackUrl:    r.Redirections.FallbackURL,
+			DesktopUrl:     r.Redirections.DesktopURL,
+			IosUrl:         r.Redirections.IosURL,
+			AndroidUrl:     r.Redirections.AndroidURL,
+			WebOnly:        r.Redirections.WebOnly,
+			DesktopWebOnly: r.Redirections.DesktopWebOnly,
+			MobileWebOnly:  r.Redirections.MobileWebOnly,
+		}
+	}
+
+	if r.DeepLinking != nil {
+		config.DeepLinking = &caerustypes.DeepLinkConfig{
+			DeepLinkPath:        r.DeepLinking.DeepLinkPath,
+			AndroidDeepLinkPath: r.DeepLinking.AndroidDeepLinkPath,
+			IosDeepLinkPath:     r.DeepLinking.IosDeepLinkPath,
+			DesktopDeepLinkPath: r.DeepLinking.DesktopDeepLinkPath,
+		}
+	}
+
+	return config, nil
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+const (
+	LinkTypeAddress     = "address"
+	LinkTypeViewProfile = "view-profile"
+	LinkTypeSend        = "send"
+	LinkTypeCustom      = "custom"
+)
+
+// CreateLinksBatchRequest represents the request sent to create multiple links at once
+type CreateLinksBatchRequest struct {
+	Requests []*BatchLinkRequest `json:"requests"`
+
+	// CreatorKey represents the identifier of the API key used to create the links, if any
+	CreatorKey string `json:"-"`
+}
+
+// BatchLinkRequest represents a single link creation request inside a CreateLinksBatchRequest
+type BatchLinkRequest struct {
+	// Type represents the type of link to be created.
+	// It can be either LinkTypeAddress, LinkTypeViewProfile, LinkTypeSend or LinkTypeCustom
+	Type string `json:"type"`
+
+	// Address represents the address for which to create the link.
+	// Required by all types except LinkTypeCustom
+	Address string `json:"address,omitempty"`
+
+	// ChainType represents the chain for which the link should be created (either "mainnet" or "testnet").
+	// Required by all types except LinkTypeCustom
+	ChainType string `json:"chain_type,omitempty"`
+
+	// Amount represents the optional amount of tokens to send (e.g. "10udaric").
+	// Used only by LinkTypeSend
+	Amount string `json:"amount,omitempty"`
+
+	// Config represents the configuration of the link to be created.
+	// Required only by LinkTypeCustom
+	Config *CreateLinkRequest `json:"config,omitempty"`
+
+	// ForceNew tells whether a new link should be created even if one already exists for the same request
+	ForceNew bool `json:"force_new,omitempty"`
+}
+
+// CreateLinksBatchResponse represents the response returned when a batch of links is created
+type CreateLinksBatchResponse struct {
+	// Results contains the result of each request, in the same order of the requests
+	Results []*BatchLinkResult `json:"results"`
+}
+
+// BatchLinkResult represents the result of a single BatchLinkRequest
+type BatchLinkResult struct {
+	// DeepLink represents the URL of the generated deep link, if the creation succeeded
+	DeepLink string `json:"deep_link,omitempty"`
+
+	// Status represents the HTTP status code associated with the error, if the creation failed
+	Status int `json:"status,omitempty"`
+
+	// Code represents the machine-readable code of the error, if the creation failed
+	Code string `json:"code,omitempty"`
+
+	// Error represents the description of the error that occurred while creating the link, if any
+	Error string `json:"error,omitempty"`
+
+	// Param represents the name of the request field that caused the error, if any
+	Param string `json:"param,omitempty"`
+}
+
+func NewBatchLinkSuccess(deepLink string) *BatchLinkResult {
+	return &BatchLinkResult{
+		DeepLink: deepLink,
+	}
+}
+
+func NewBatchLinkFailure(err error) *BatchLinkResult {
+	httpErr := utils.UnwrapErr(err)
+	return &BatchLinkResult{
+		Status: httpErr.StatusCode,
+		Code:   httpErr.Code,
+		Error:  httpErr.Message,
+		Param:  httpErr.Param,
+	}
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// GetAddressLinksRequest represents the request sent to get the links associated with an address
+type GetAddressLinksRequest struct {
+	// Address represents the address for which to get the links
+	Address string
+
+	// Pagination contains the pagination data used to get the links
+	Pagination *types.Pagination
+}
+
+func NewGetAddressLinksRequest(address string, pagination *types.Pagination) *GetAddressLinksRequest {
+	return &GetAddressLinksRequest{
+		Address:    address,
+		Pagination: pagination,
+	}
+}
+
+// GetAddressLinksResponse represents the response returned when the links associated with an address are retrieved
+type GetAddressLinksResponse struct {
+	Links      []*types.CreatedLink `json:"links"`
+	Pagination *PaginationResponse  `json:"pagination"`
+}
+
+// PaginationResponse contains the pagination data of a paginated response
+type PaginationResponse struct {
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+	Total  uint64 `json:"total"`
+}
+
+func NewGetAddressLinksResponse(links []*types.CreatedLink, pagination *types.Pagination, total uint64) *GetAddressLinksResponse {
+	if links == nil {
+		links = []*types.CreatedLink{}
+	}
+
+	return &GetAddressLinksResponse{
+		Links: links,
+		Pagination: &PaginationResponse{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+}
@@ -0,0 +1,8 @@
+package tracing
+
+const (
+	EnvTracingExporter      = "TRACING_EXPORTER"
+	EnvTracingFilePath      = "TRACING_FILE_PATH"
+	EnvTracingSamplingRatio = "TRACING_SAMPLING_RATIO"
+	EnvTracingServiceName   = "TRACING_SERVICE_NAME"
+)
@@ -0,0 +1,226 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	caerusutils "github.com/desmos-labs/caerus/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+const (
+	// ExporterNone disables the tracing
+	ExporterNone = "none"
+
+	// ExporterOTLP exports the spans to an OTLP collector over gRPC. The collector is configured using the
+	// standard OTEL_EXPORTER_OTLP_* env variables (e.g. OTEL_EXPORTER_OTLP_ENDPOINT)
+	ExporterOTLP = "otlp"
+
+	// ExporterStdout writes the spans to the standard output
+	ExporterStdout = "stdout"
+
+	// ExporterFile writes the spans to a file
+	ExporterFile = "file"
+)
+
+// Config contains the configuration of the tracing
+type Config struct {
+	// Exporter represents the exporter used to export the spans
+	// (either ExporterNone, ExporterOTLP, ExporterStdout or ExporterFile)
+	Exporter string
+
+	// FilePath represents the path of the file where the spans are written when using ExporterFile
+	FilePath string
+
+	// SamplingRatio represents the ratio of the traces that are sampled, between 0 and 1.
+	// Traces started by the callers are sampled based on their own sampling decision
+	SamplingRatio float64
+
+	// ServiceName represents the name of the service that is attached to all the spans
+	ServiceName string
+}
+
+// DefaultConfig returns the default Config instance
+func DefaultConfig() *Config {
+	return &Config{
+		Exporter:      ExporterNone,
+		SamplingRatio: 1,
+		ServiceName:   "dpm-apis",
+	}
+}
+
+// ReadConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// It returns an error if any value is not valid
+func ReadConfigFromEnvVariables() (*Config, error) {
+	cfg := DefaultConfig()
+	cfg.Exporter = caerusutils.GetEnvOr(EnvTracingExporter, cfg.Exporter)
+	cfg.FilePath = caerusutils.GetEnvOr(EnvTracingFilePath, cfg.FilePath)
+	cfg.ServiceName = caerusutils.GetEnvOr(EnvTracingServiceName, cfg.ServiceName)
+
+	if ratioValue := caerusutils.GetEnvOr(EnvTracingSamplingRatio, ""); ratioValue != "" {
+		ratio, err := strconv.ParseFloat(ratioValue, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", EnvTracingSamplingRatio, err)
+		}
+		cfg.SamplingRatio = ratio
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// NewConfigFromEnvVariables returns a new Config instance reading the values from the env variables.
+// It panics if any value is not valid
+func NewConfigFromEnvVariables() *Config {
+	cfg, err := ReadConfigFromEnvVariables()
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// Validate checks whether the configuration is valid or not
+func (c *Config) Validate() error {
+	switch c.Exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+		break
+	case ExporterFile:
+		if c.FilePath == "" {
+			return fmt.Errorf("missing %s", EnvTracingFilePath)
+		}
+	default:
+		return fmt.Errorf("invalid %s: must be one of %s, %s, %s or %s",
+			EnvTracingExporter, ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile)
+	}
+
+	if c.SamplingRatio < 0 || c.SamplingRatio > 1 {
+		return fmt.Errorf("invalid %s: must be between 0 and 1", EnvTracingSamplingRatio)
+	}
+
+	return nil
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// Tracing contains the tracing setup of the application
+type Tracing struct {
+	cfg      *Config
+	provider *sdktrace.TracerProvider
+	output   io.Closer
+}
+
+// Setup sets up the global tracer provider based on the given configuration.
+// The returned Tracing instance must be stopped before exiting so that all the pending spans are exported
+func Setup(cfg *Config) (*Tracing, error) {
+	// Propagate the trace context even if the tracing is disabled, so that the traces started by the callers
+	// are not interrupted
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	tracing := &Tracing{cfg: cfg}
+	if cfg.Exporter == ExporterNone {
+		return tracing, nil
+	}
+
+	exporter, err := tracing.buildExporter()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	tracing.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+	)
+	otel.SetTracerProvider(tracing.provider)
+
+	log.Info().Str("exporter", cfg.Exporter).Float64("sampling_ratio", cfg.SamplingRatio).Msg("Tracing enabled")
+	return tracing, nil
+}
+
+// SetupFromEnvVariables sets up the global tracer provider reading the configuration from the env variables.
+// It panics if the configuration is not valid or the tracing cannot be set up
+func SetupFromEnvVariables() *Tracing {
+	tracing, err := Setup(NewConfigFromEnvVariables())
+	if err != nil {
+		panic(fmt.Errorf("error while setting up the tracing: %s", err))
+	}
+	return tracing
+}
+
+// buildExporter builds the span exporter based on the configuration
+func (t *Tracing) buildExporter() (sdktrace.SpanExporter, error) {
+	switch t.cfg.Exporter {
+	case ExporterOTLP:
+		return otlptracegrpc.New(context.Background())
+
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+
+	case ExporterFile:
+		file, err := os.OpenFile(t.cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		t.output = file
+		return stdouttrace.New(stdouttrace.WithWriter(file))
+
+	default:
+		return nil, fmt.Errorf("unsupported exporter: %s", t.cfg.Exporter)
+	}
+}
+
+// Middleware returns a Gin handler function that starts a new span for each request, continuing the trace
+// started by the caller if any. Requests to the given paths are not traced
+func (t *Tracing) Middleware(ignoredPaths ...string) gin.HandlerFunc {
+	return otelgin.Middleware(t.cfg.ServiceName, otelgin.WithFilter(func(request *http.Request) bool {
+		for _, path := range ignoredPaths {
+			if request.URL.Path == path {
+				return false
+			}
+		}
+		return true
+	}))
+}
+
+// Stop exports all the pending spans and releases the resources used by the tracing
+func (t *Tracing) Stop(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	err := t.provider.Shutdown(ctx)
+	if err != nil {
+		return err
+	}
+
+	if t.output != nil {
+		return t.output.Close()
+	}
+
+	return nil
+}
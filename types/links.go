@@ -0,0 +1,61 @@
+package types
+
+import (
+	"time"
+)
+
+// CreatedLink contains the data of a link that has been created
+type CreatedLink struct {
+	// URL represents the URL of the created link
+	URL string `json:"url" db:"link_url"`
+
+	// Action represents the action performed by the link (e.g. "send")
+	Action string `json:"action" db:"action"`
+
+	// Address represents the address associated with the link, if any
+	Address string `json:"address,omitempty" db:"address"`
+
+	// Amount represents the amount of tokens associated with the link, if any
+	Amount string `json:"amount,omitempty" db:"amount"`
+
+	// ChainType represents the chain type associated with the link, if any
+	ChainType string `json:"chain_type,omitempty" db:"chain_type"`
+
+	// CreatorKey represents the identifier of the API key used to create the link, if any
+	CreatorKey string `json:"creator_key,omitempty" db:"creator_key"`
+
+	// CreationTime represents the time at which the link has been created
+	CreationTime time.Time `json:"creation_time" db:"creation_time"`
+}
+
+func NewCreatedLink(
+	url string, action string, address string, amount string, chainType string, creatorKey string, creationTime time.Time,
+) *CreatedLink {
+	return &CreatedLink{
+		URL:          url,
+		Action:       action,
+		Address:      address,
+		Amount:       amount,
+		ChainType:    chainType,
+		CreatorKey:   creatorKey,
+		CreationTime: creationTime,
+	}
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+
+// Pagination contains the data used to paginate a list of results
+type Pagination struct {
+	// Offset represents the number of results to skip
+	Offset uint64 `json:"offset"`
+
+	// Limit represents the maximum number of results to return
+	Limit uint64 `json:"limit"`
+}
+
+func NewPagination(offset uint64, limit uint64) *Pagination {
+	return &Pagination{
+		Offset: offset,
+		Limit:  limit,
+	}
+}
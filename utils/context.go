@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeout returns a Gin handler function that sets a deadline to the context of each request, so that all
+// the operations performed while handling a request are canceled once the given timeout expires.
+// The request context is also canceled as soon as the client closes the connection
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// DetachedContext returns a context that carries the values of the given one, but that is never canceled and has
+// no deadline. It allows an operation started while handling a request to complete even if such request goes away,
+// and should be bounded using its own timeout
+func DetachedContext(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+// detachedContext is the context returned by DetachedContext
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	caerusutils "github.com/desmos-labs/caerus/utils"
+)
+
+// ParsePositiveIntEnvOr returns the positive integer value of the env variable having the given name,
+// or the given default value if the variable is not set.
+// It returns an error if the variable is set to an invalid value
+func ParsePositiveIntEnvOr(envName string, defaultValue int) (int, error) {
+	valueStr := caerusutils.GetEnvOr(envName, "")
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be a positive integer", envName)
+	}
+
+	return value, nil
+}
+
+// GetPositiveIntEnvOr returns the positive integer value of the env variable having the given name,
+// or the given default value if the variable is not set.
+// It panics if the variable is set to an invalid value
+func GetPositiveIntEnvOr(envName string, defaultValue int) int {
+	value, err := ParsePositiveIntEnvOr(envName, defaultValue)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// ParseDurationEnvOr returns the duration value of the env variable having the given name (e.g. "30s"),
+// or the given default value if the variable is not set.
+// It returns an error if the variable is set to an invalid value
+func ParseDurationEnvOr(envName string, defaultValue time.Duration) (time.Duration, error) {
+	valueStr := caerusutils.GetEnvOr(envName, "")
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be a positive duration", envName)
+	}
+
+	return value, nil
+}
+
+// GetDurationEnvOr returns the duration value of the env variable having the given name (e.g. "30s"),
+// or the given default value if the variable is not set.
+// It panics if the variable is set to an invalid value
+func GetDurationEnvOr(envName string, defaultValue time.Duration) time.Duration {
+	value, err := ParseDurationEnvOr(envName, defaultValue)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
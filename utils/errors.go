@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error codes that are returned to the users, allowing them to handle the errors without relying on their messages.
+// These codes are part of the public APIs, so they must never be changed
+const (
+	// Generic errors
+	ErrCodeBadRequest         = "BAD_REQUEST"
+	ErrCodeInvalidRequestBody = "INVALID_REQUEST_BODY"
+	ErrCodeInvalidParam       = "INVALID_PARAM"
+	ErrCodeMissingParam       = "MISSING_PARAM"
+	ErrCodeNotFound           = "NOT_FOUND"
+	ErrCodeConflict           = "CONFLICT"
+	ErrCodeRateLimited        = "RATE_LIMITED"
+	ErrCodeRequestCanceled    = "REQUEST_CANCELED"
+	ErrCodeInternal           = "INTERNAL_ERROR"
+
+	// Authentication errors
+	ErrCodeUnauthorized      = "UNAUTHORIZED"
+	ErrCodeInsufficientScope = "INSUFFICIENT_SCOPE"
+	ErrCodeAddressNotOwned   = "ADDRESS_NOT_OWNED"
+	ErrCodeInvalidSignature  = "INVALID_SIGNATURE"
+
+	// Links errors
+	ErrCodeInvalidAddress    = "INVALID_ADDRESS"
+	ErrCodeInvalidChainType  = "INVALID_CHAIN_TYPE"
+	ErrCodeInvalidAmount     = "INVALID_AMOUNT"
+	ErrCodeInvalidPagination = "INVALID_PAGINATION"
+	ErrCodeInvalidFormat     = "INVALID_FORMAT"
+	ErrCodeInvalidQROptions  = "INVALID_QR_OPTIONS"
+	ErrCodeInvalidLinkConfig = "INVALID_LINK_CONFIG"
+	ErrCodeInvalidLinkType   = "INVALID_LINK_TYPE"
+	ErrCodeInvalidBatch      = "INVALID_BATCH"
+	ErrCodeLinkNotFound      = "LINK_NOT_FOUND"
+
+	// Upstream errors
+	ErrCodeUpstreamError       = "UPSTREAM_ERROR"
+	ErrCodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	ErrCodeUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+	ErrCodeUpstreamRateLimited = "UPSTREAM_RATE_LIMITED"
+)
+
+// HttpError represents an error that can be returned to the user
+type HttpError struct {
+	// StatusCode represents the HTTP status code of the response
+	StatusCode int
+
+	// Code represents the machine-readable code of the error (e.g. ErrCodeInvalidAddress)
+	Code string
+
+	// Message represents the human-readable description of the error
+	Message string
+
+	// Param represents the name of the request parameter that caused the error, if any
+	Param string
+
+	// Cause represents the error that caused this one, if any.
+	// It is included in the logs but never returned to the user
+	Cause error
+}
+
+func (e *HttpError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("status %d (%s): %s: %s", e.StatusCode, e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("status %d (%s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (e *HttpError) Unwrap() error {
+	return e.Cause
+}
+
+// WithParam sets the name of the request parameter that caused the error
+func (e *HttpError) WithParam(param string) *HttpError {
+	e.Param = param
+	return e
+}
+
+// WithCause sets the error that caused this one. The cause is only logged, and never returned to the user
+func (e *HttpError) WithCause(cause error) *HttpError {
+	e.Cause = cause
+	return e
+}
+
+// WrapErr returns a new error that contains the given status code, error code and message
+func WrapErr(statusCode int, code string, message string) *HttpError {
+	return &HttpError{
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    message,
+	}
+}
+
+// UnwrapErr unwraps the given error returning the HttpError it contains.
+// Errors that do not contain an HttpError are considered internal errors, and their details are hidden
+func UnwrapErr(err error) *HttpError {
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	return WrapErr(http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+}
+
+// StatusText returns the text describing the given status code, supporting the non-standard ones used by the APIs
+func StatusText(statusCode int) string {
+	if statusCode == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(statusCode)
+}
@@ -1,56 +1,80 @@
 package utils
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
-	"unicode"
 
 	"github.com/gin-gonic/gin"
 )
 
-type HttpError struct {
-	StatusCode int
-	Response   string
-}
+const (
+	// StatusClientClosedRequest represents the non-standard status code used when the client closes the connection
+	// before the request has been handled
+	StatusClientClosedRequest = 499
 
-func (e *HttpError) Error() string {
-	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Response)
-}
+	// ProblemContentType represents the content type of the error responses, as defined by RFC 7807
+	ProblemContentType = "application/problem+json"
 
-// WrapErr wraps the given error into a new one that contains the given status code and response
-func WrapErr(statusCode int, res string) error {
-	return &HttpError{
-		StatusCode: statusCode,
-		Response:   res,
-	}
-}
+	// APIKeyIDContextKey represents the key of the context value containing the identifier of the API key used
+	// to perform the request. It is set by the authentication middlewares
+	APIKeyIDContextKey = "api_key_id"
 
-func ucFirst(str string) string {
-	for i, v := range str {
-		return string(unicode.ToUpper(v)) + str[i+1:]
-	}
-	return ""
-}
+	// WalletAddressContextKey represents the key of the context value containing the address of the user that
+	// performed the request. It is set by the authentication middlewares when using a wallet session token
+	WalletAddressContextKey = "wallet_address"
+)
 
-// UnwrapErr unwraps the given error returning the status code and response
-func UnwrapErr(err error) (statusCode int, res string) {
-	if httpErr, ok := err.(*HttpError); ok {
-		return httpErr.StatusCode, httpErr.Response
-	}
-	return http.StatusInternalServerError, ucFirst(err.Error())
+// ProblemResponse represents the body of the error responses, following the RFC 7807 format
+type ProblemResponse struct {
+	// Type represents the URI identifying the problem type.
+	// Since the problems are identified by their Code, it is always "about:blank"
+	Type string `json:"type"`
+
+	// Title represents the description of the status code
+	Title string `json:"title"`
+
+	// Status represents the status code of the response
+	Status int `json:"status"`
+
+	// Detail represents the human-readable description of the error
+	Detail string `json:"detail"`
+
+	// Instance represents the path of the request that caused the error
+	Instance string `json:"instance,omitempty"`
+
+	// Code represents the machine-readable code of the error
+	Code string `json:"code"`
+
+	// Param represents the name of the request parameter that caused the error, if any
+	Param string `json:"param,omitempty"`
+
+	// RequestID represents the identifier of the request that caused the error
+	RequestID string `json:"request_id,omitempty"`
 }
 
-type errorJsonResponse struct {
-	Error string `json:"error"`
+// NewProblemResponse returns a new ProblemResponse instance for the given error
+func NewProblemResponse(err *HttpError, instance string, requestID string) *ProblemResponse {
+	return &ProblemResponse{
+		Type:      "about:blank",
+		Title:     StatusText(err.StatusCode),
+		Status:    err.StatusCode,
+		Detail:    err.Message,
+		Instance:  instance,
+		Code:      err.Code,
+		Param:     err.Param,
+		RequestID: requestID,
+	}
 }
 
 // HandleError handles the given error by returning the proper response
 func HandleError(c *gin.Context, err error) {
-	statusCode, res := UnwrapErr(err)
+	httpErr := UnwrapErr(err)
 	c.Abort()
 	_ = c.Error(err)
-	c.JSON(statusCode, errorJsonResponse{Error: res})
+
+	// Set the content type before rendering the JSON, so that it is not overridden
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(httpErr.StatusCode, NewProblemResponse(httpErr, c.Request.URL.Path, GetRequestID(c)))
 }
 
 // GetTokenValue returns the token value associated with the given context, reading it from the Authorization header
@@ -58,8 +82,20 @@ func GetTokenValue(c *gin.Context) (string, error) {
 	headerValue := c.GetHeader("Authorization")
 	token := strings.TrimSpace(strings.TrimPrefix(headerValue, "Bearer"))
 	if token == "" {
-		return "", WrapErr(http.StatusUnauthorized, "wrong Authorization header value")
+		return "", WrapErr(http.StatusUnauthorized, ErrCodeUnauthorized, "wrong Authorization header value")
 	}
 
 	return token, nil
 }
+
+// GetAPIKeyID returns the identifier of the API key used to perform the request associated with the given context,
+// or an empty string if the request has not been authenticated using an API key
+func GetAPIKeyID(c *gin.Context) string {
+	return c.GetString(APIKeyIDContextKey)
+}
+
+// GetWalletAddress returns the address of the user that performed the request associated with the given context,
+// or an empty string if the request has not been authenticated using a wallet session token
+func GetWalletAddress(c *gin.Context) string {
+	return c.GetString(WalletAddressContextKey)
+}
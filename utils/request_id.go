@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader represents the header used to propagate the identifier of the requests
+	RequestIDHeader = "X-Request-ID"
+
+	// RequestIDContextKey represents the key of the context value containing the identifier of the request
+	RequestIDContextKey = "request_id"
+)
+
+// requestIDKey represents the key of the request context value containing the identifier of the request
+type requestIDKey struct{}
+
+var (
+	// requestIDRegex matches the request identifiers that can be propagated from the callers
+	requestIDRegex = regexp.MustCompile(`^[a-zA-Z0-9._:-]{1,128}$`)
+)
+
+// RequestID returns a Gin handler function that assigns an identifier to each request, so that it can be used to
+// correlate the logs and errors. The identifier provided by the caller using the RequestIDHeader is used if valid,
+// otherwise a new one is generated. The identifier is returned to the caller using the same header, and is stored
+// inside the request context so that it can be forwarded to the upstream services
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDRegex.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDContextKey, requestID)
+		c.Request = c.Request.WithContext(ContextWithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// newRequestID returns a new random request identifier
+func newRequestID() string {
+	bz := make([]byte, 16)
+	_, _ = rand.Read(bz)
+	return hex.EncodeToString(bz)
+}
+
+// GetRequestID returns the identifier of the request associated with the given context,
+// or an empty string if no identifier has been assigned to it
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDContextKey)
+}
+
+// ContextWithRequestID returns a new context, derived from the given one, containing the given request identifier
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request identifier contained inside the given context,
+// or an empty string if it does not contain any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}